@@ -0,0 +1,14 @@
+-- items
+INSERT INTO item (id, name) VALUES (9, 'it''s; a "sword"');
+/* block; comment */
+INSERT INTO item (id, name)
+VALUES (10, 'back\'slash;');
+
+DELIMITER //
+CREATE PROCEDURE add_item(IN n VARCHAR(255))
+BEGIN
+  INSERT INTO item (name) VALUES (n);
+END//
+DELIMITER ;
+/*!40101 SET NAMES utf8mb4 */;
+# trailing comment
@@ -0,0 +1,175 @@
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadArchive loads fixture files in zip or tar (.tar, .tar.gz, .tgz) archive.
+// Each entry is loaded into the table of its base name, and all tables are loaded
+// in one transaction in the order of foreign key dependency.
+// Errors of an entry have the file name `archive:entry` such as `fixtures.zip:item.csv`.
+func (fl FixtureLoader) LoadArchive(archive string, options ...Option) error {
+	_, err := fl.LoadArchiveWithResult(archive, options...)
+	return err
+}
+
+// LoadArchiveWithResult is LoadArchive and returns the result of each loaded table
+func (fl FixtureLoader) LoadArchiveWithResult(archive string, options ...Option) ([]LoadResult, error) {
+	f := fl
+	for _, option := range options {
+		if err := option(&f); err != nil {
+			return nil, errors.Wrap(err, "error invalid option")
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "fixture-archive")
+	if err != nil {
+		return nil, errors.Wrap(err, "create temporary directory error")
+	}
+	defer os.RemoveAll(dir)
+
+	entries, err := extractArchive(archive, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// the table of each entry is taken from its base name
+	f.table = ""
+	fixtures := make([]fixture, 0, len(entries))
+	for _, entry := range entries {
+		fxs, err := f.namedFixtures(entry.file, archive+":"+entry.name)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fxs...)
+	}
+
+	// an archive is loaded in one transaction
+	f.parallelism = 0
+	return f.loadFixtureSet(fixtures)
+}
+
+// archiveEntry is a fixture file extracted from archive
+type archiveEntry struct {
+	name string // name in archive
+	file string // extracted file
+}
+
+// extractArchive extracts fixture files in archive into dir, and returns extracted entries in the order of the archive.
+// Directories and dotfiles are skipped. Each entry is extracted into its own directory with its base name.
+func extractArchive(archive, dir string) ([]archiveEntry, error) {
+	var entries []archiveEntry
+	extract := func(name string, r io.Reader) error {
+		base := path.Base(name)
+		if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+			return nil
+		}
+
+		entryDir := filepath.Join(dir, fmt.Sprint(len(entries)))
+		if err := os.Mkdir(entryDir, 0700); err != nil {
+			return err
+		}
+
+		file := filepath.Join(entryDir, base)
+		w, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		if _, err := io.Copy(w, r); err != nil {
+			return err
+		}
+		entries = append(entries, archiveEntry{name: name, file: file})
+
+		return w.Close()
+	}
+
+	var err error
+	if strings.HasSuffix(archive, ".zip") {
+		err = extractZip(archive, extract)
+	} else if _, name := compression(archive); strings.HasSuffix(name, ".tar") || strings.HasSuffix(archive, ".tgz") {
+		err = extractTar(archive, extract)
+	} else {
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "archive: %s is not zip or tar", archive)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "archive: %s extract error", archive)
+	}
+
+	return entries, nil
+}
+
+func extractZip(archive string, extract func(string, io.Reader) error) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, entry := range r.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = extract(entry.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractTar(archive string, extract func(string, io.Reader) error) error {
+	rc, err := openFixture(archive)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if strings.HasSuffix(archive, ".tgz") {
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := extract(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestExtractArchive(t *testing.T) {
+	for _, archive := range []string{"_data/fixtures.zip", "_data/fixtures.tar.gz"} {
+		t.Run(archive, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "fixture-archive-test")
+			if err != nil {
+				t.Fatalf("[error] temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			entries, err := extractArchive(archive, dir)
+			if err != nil {
+				t.Fatalf("[error] extract archive: %v", err)
+			}
+
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.name)
+				if filepath.Base(entry.file) != path.Base(entry.name) {
+					t.Fatalf("[error] extract archive: %s is extracted to %s", entry.name, entry.file)
+				}
+			}
+			expect := []string{"fixtures/player_item.csv", "fixtures/guild.csv", "fixtures/player.csv"}
+			if !reflect.DeepEqual(names, expect) {
+				t.Fatalf("[error] extract archive: expect: %v but %v", expect, names)
+			}
+
+			b, err := ioutil.ReadFile(entries[1].file)
+			if err != nil {
+				t.Fatalf("[error] read file: %v", err)
+			}
+			want, _ := ioutil.ReadFile("_data/fixtures/guild.csv")
+			if string(b) != string(want) {
+				t.Fatalf("[error] extract archive: expect: %s but %s", want, b)
+			}
+		})
+	}
+
+	t.Run("unsupported archive", func(t *testing.T) {
+		_, err := extractArchive("_data/item.csv", os.TempDir())
+		if !errors.Is(err, ErrUnsupportedFormat) {
+			t.Fatalf("[error] extract archive: expect: %v but %v", ErrUnsupportedFormat, err)
+		}
+	})
+}
+
+func TestNamedFixtures(t *testing.T) {
+	name := "fixtures.zip:malformed/broken.json"
+	_, err := FixtureLoader{}.namedFixtures("_data/malformed/broken.json", name)
+	if !errors.Is(err, ErrMalformedFixture) || !strings.Contains(err.Error(), "file: "+name) {
+		t.Fatalf("[error] named fixtures: expect: error of %s but %v", name, err)
+	}
+
+	fixtures, err := FixtureLoader{}.namedFixtures("_data/item.csv", "fixtures.zip:item.csv")
+	if err != nil {
+		t.Fatalf("[error] named fixtures: %v", err)
+	}
+	if fixtures[0].name != "fixtures.zip:item.csv" || fixtures[0].table != "item" {
+		t.Fatalf("[error] named fixtures: expect: item of fixtures.zip:item.csv but %v", fixtures[0])
+	}
+}
@@ -0,0 +1,61 @@
+package loader
+
+import (
+	"github.com/pkg/errors"
+)
+
+// mappedSource is rowSource which renames columns by ColumnMap and drops IgnoreColumns
+type mappedSource struct {
+	rowSource
+	header []string
+	names  map[string]string // column in fixture: column in table
+}
+
+func newMappedSource(src rowSource, columnMap map[string]string, ignore map[string]bool) (*mappedSource, error) {
+	columns := src.columns()
+	s := &mappedSource{
+		rowSource: src,
+		header:    make([]string, 0, len(columns)),
+		names:     make(map[string]string, len(columns)),
+	}
+
+	seen := make(map[string]string, len(columns))
+	for _, column := range columns {
+		name := column
+		if to, ok := columnMap[column]; ok {
+			name = to
+		}
+		if ignore[column] || ignore[name] {
+			continue
+		}
+		if from, ok := seen[name]; ok {
+			return nil, errors.Wrapf(ErrMalformedFixture, "columns %s and %s are mapped to the same column %s", from, column, name)
+		}
+		seen[name] = column
+
+		s.header = append(s.header, name)
+		s.names[column] = name
+	}
+
+	return s, nil
+}
+
+func (s *mappedSource) columns() []string {
+	return s.header
+}
+
+func (s *mappedSource) next() (map[string]string, error) {
+	row, err := s.rowSource.next()
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make(map[string]string, len(s.names))
+	for column, value := range row {
+		if name, ok := s.names[column]; ok {
+			mapped[name] = value
+		}
+	}
+
+	return mapped, nil
+}
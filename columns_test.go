@@ -0,0 +1,56 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestMappedSource(t *testing.T) {
+	data := Data{
+		columns: []string{"Item ID", "Item Name", "Note"},
+		rows: []map[string]string{
+			map[string]string{"Item ID": "1", "Item Name": "エクスカリバー", "Note": "legendary"},
+		},
+	}
+
+	t.Run("rename and ignore columns", func(t *testing.T) {
+		src, err := newMappedSource(newDataSource(data), map[string]string{"Item ID": "id", "Item Name": "name"}, map[string]bool{"Note": true})
+		if err != nil {
+			t.Fatalf("[error] new mapped source: %v", err)
+		}
+
+		mapped, err := readAll(src)
+		if err != nil {
+			t.Fatalf("[error] read mapped source: %v", err)
+		}
+
+		if !reflect.DeepEqual(mapped.columns, []string{"id", "name"}) {
+			t.Fatalf("[error] mapped columns: expect: %v but %v", []string{"id", "name"}, mapped.columns)
+		}
+
+		rows := []map[string]string{map[string]string{"id": "1", "name": "エクスカリバー"}}
+		if !reflect.DeepEqual(mapped.rows, rows) {
+			t.Fatalf("[error] mapped rows: expect: %v but %v", rows, mapped.rows)
+		}
+	})
+
+	t.Run("ignore renamed column", func(t *testing.T) {
+		src, err := newMappedSource(newDataSource(data), map[string]string{"Note": "note"}, map[string]bool{"note": true})
+		if err != nil {
+			t.Fatalf("[error] new mapped source: %v", err)
+		}
+
+		if !reflect.DeepEqual(src.columns(), []string{"Item ID", "Item Name"}) {
+			t.Fatalf("[error] mapped columns: expect: %v but %v", []string{"Item ID", "Item Name"}, src.columns())
+		}
+	})
+
+	t.Run("columns mapped to the same column", func(t *testing.T) {
+		_, err := newMappedSource(newDataSource(data), map[string]string{"Item ID": "id", "Note": "id"}, nil)
+		if !errors.Is(err, ErrMalformedFixture) {
+			t.Fatalf("[error] new mapped source: expect: %v but %v", ErrMalformedFixture, err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package loader
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// compressions are suffixes of compressed fixture files
+var compressions = []string{".gz", ".zst", ".bz2"}
+
+// compression returns the compression suffix of file and file name without it
+func compression(file string) (string, string) {
+	for _, suffix := range compressions {
+		if strings.HasSuffix(file, suffix) {
+			return suffix, strings.TrimSuffix(file, suffix)
+		}
+	}
+
+	return "", file
+}
+
+// fixtureReader is io.ReadCloser of fixture file which closes the decompressor and the file
+type fixtureReader struct {
+	io.Reader
+	closers []func() error
+}
+
+func (r *fixtureReader) Close() error {
+	var err error
+	for _, close := range r.closers {
+		if e := close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// openFixture opens file and decompresses it by the compression suffix
+func openFixture(file string) (io.ReadCloser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "file: %s open error", file)
+	}
+
+	suffix, _ := compression(file)
+	switch suffix {
+	case ".gz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "file: %s gzip error", file)
+		}
+		return &fixtureReader{Reader: gz, closers: []func() error{gz.Close, f.Close}}, nil
+	case ".zst":
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "file: %s zstd error", file)
+		}
+		closeZstd := func() error {
+			zr.Close()
+			return nil
+		}
+		return &fixtureReader{Reader: zr, closers: []func() error{closeZstd, f.Close}}, nil
+	case ".bz2":
+		return &fixtureReader{Reader: bzip2.NewReader(f), closers: []func() error{f.Close}}, nil
+	}
+
+	return f, nil
+}
+
+// readFixture reads all of file decompressing it
+func readFixture(file string) ([]byte, error) {
+	r, err := openFixture(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "file: %s read error", file)
+	}
+
+	return b, nil
+}
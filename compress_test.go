@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	type Test struct {
+		Title  string
+		File   string
+		Suffix string
+		Name   string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "gzip",
+			File:   "_data/item.csv.gz",
+			Suffix: ".gz",
+			Name:   "_data/item.csv",
+		},
+		Test{
+			Title:  "zstd",
+			File:   "_data/item.yaml.zst",
+			Suffix: ".zst",
+			Name:   "_data/item.yaml",
+		},
+		Test{
+			Title:  "not compressed",
+			File:   "_data/item.csv",
+			Suffix: "",
+			Name:   "_data/item.csv",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			suffix, name := compression(test.File)
+			if suffix != test.Suffix || name != test.Name {
+				t.Fatalf("[error] compression: expect: %s %s but %s %s", test.Suffix, test.Name, suffix, name)
+			}
+		})
+	}
+}
+
+func TestReadFixture(t *testing.T) {
+	for _, file := range []string{"_data/item.csv.gz", "_data/item.json.bz2", "_data/item.yaml.zst"} {
+		t.Run(file, func(t *testing.T) {
+			_, name := compression(file)
+			expect, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatalf("[error] read file: %v", err)
+			}
+
+			b, err := readFixture(file)
+			if err != nil {
+				t.Fatalf("[error] read fixture: %v", err)
+			}
+
+			if !reflect.DeepEqual(b, expect) {
+				t.Fatalf("[error] read fixture: expect: %s but %s", expect, b)
+			}
+		})
+	}
+}
+
+func TestGetDataFromCompressedFile(t *testing.T) {
+	fx := FixtureLoader{}
+
+	format, err := fx.fileFormat("_data/item.csv.gz")
+	if err != nil || format != "csv" {
+		t.Fatalf("[error] file format: expect: csv but %s %v", format, err)
+	}
+
+	data, err := readTestFile(fx, format, "_data/item.csv.gz")
+	if err != nil {
+		t.Fatalf("[error] get data from csv: %v", err)
+	}
+
+	rows := []map[string]string{map[string]string{"id": "1", "name": "エクスカリバー"}, map[string]string{"id": "2", "name": "村正"}}
+	if !reflect.DeepEqual(data.rows, rows) {
+		t.Fatalf("[error] get data from csv: expect: %v but %v", rows, data.rows)
+	}
+}
@@ -0,0 +1,103 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/lib/pq"
+	"github.com/shogo82148/txmanager"
+)
+
+// canCopy reports whether rows can be loaded by COPY FROM STDIN.
+func (f FixtureLoader) canCopy() bool {
+	return f.driver == PostgreSQL && f.bulkInsert
+}
+
+// execCopy loads rows of src by `COPY table (columns) FROM STDIN` with the copy-in support of lib/pq.
+// COPY can't express DEFAULT, so empty values are left out of the column list to be loaded as DEFAULT
+// as INSERT does. A new COPY is started when the columns with values change from the previous row,
+// and a row without values is inserted by `INSERT ... DEFAULT VALUES`.
+func (f FixtureLoader) execCopy(tx txmanager.Tx, columns []string, src *countingSource, result *LoadResult) error {
+	var stmt *sql.Stmt
+	var copying []string
+	var rows int64
+	defer func() {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}()
+
+	// flush ends the current COPY
+	flush := func() error {
+		if stmt == nil {
+			return nil
+		}
+		res, err := stmt.Exec()
+		if err != nil {
+			return err
+		}
+		result.addAffected(rows, res, false)
+		err = stmt.Close()
+		stmt, rows = nil, 0
+		return err
+	}
+
+	for {
+		row, err := src.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		present := copyColumns(columns, row)
+		if len(present) == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			res, err := tx.Exec(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quote(f.driver, f.table)))
+			if err != nil {
+				return err
+			}
+			result.addAffected(1, res, false)
+			continue
+		}
+
+		if stmt == nil || !reflect.DeepEqual(present, copying) {
+			if err := flush(); err != nil {
+				return err
+			}
+			stmt, err = tx.Prepare(pq.CopyIn(f.table, present...))
+			if err != nil {
+				return err
+			}
+			copying = present
+		}
+
+		values := make([]interface{}, len(present))
+		for i, column := range present {
+			values[i] = row[column]
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return err
+		}
+		rows++
+	}
+
+	return flush()
+}
+
+// copyColumns returns the columns which have values in row
+func copyColumns(columns []string, row map[string]string) []string {
+	present := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if row[column] != "" {
+			present = append(present, column)
+		}
+	}
+
+	return present
+}
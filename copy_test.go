@@ -0,0 +1,140 @@
+package loader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+// copyRecorder is a fake database/sql driver which records prepared and executed statements
+type copyRecorder struct {
+	prepares []string
+	execs    []string
+}
+
+// openRecorder returns DB of a new copyRecorder
+func openRecorder() (*copyRecorder, *sql.DB) {
+	recorder := &copyRecorder{}
+	return recorder, sql.OpenDB(recorder)
+}
+
+func (d *copyRecorder) Open(name string) (driver.Conn, error) {
+	return copyConn{d}, nil
+}
+
+func (d *copyRecorder) Connect(ctx context.Context) (driver.Conn, error) {
+	return copyConn{d}, nil
+}
+
+func (d *copyRecorder) Driver() driver.Driver {
+	return d
+}
+
+type copyConn struct {
+	d *copyRecorder
+}
+
+func (c copyConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.prepares = append(c.d.prepares, query)
+	return copyStmt{d: c.d, query: query}, nil
+}
+
+func (c copyConn) Close() error {
+	return nil
+}
+
+func (c copyConn) Begin() (driver.Tx, error) {
+	return copyConn{c.d}, nil
+}
+
+func (c copyConn) Commit() error {
+	return nil
+}
+
+func (c copyConn) Rollback() error {
+	return nil
+}
+
+type copyStmt struct {
+	d     *copyRecorder
+	query string
+}
+
+func (s copyStmt) Close() error {
+	return nil
+}
+
+func (s copyStmt) NumInput() int {
+	return -1
+}
+
+func (s copyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.execs = append(s.d.execs, fmt.Sprint(s.query, args))
+	return driver.ResultNoRows, nil
+}
+
+func (s copyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestExecCopy(t *testing.T) {
+	recorder, db := openRecorder()
+	defer db.Close()
+
+	data := Data{
+		columns: []string{"id", "name"},
+		rows: []map[string]string{
+			map[string]string{"id": "1", "name": "Excalibur"},
+			map[string]string{"id": "2", "name": "Masamune"},
+			map[string]string{"id": "", "name": "Muramasa"},
+			map[string]string{"id": "", "name": ""},
+			map[string]string{"id": "5", "name": "Durandal"},
+		},
+	}
+
+	tx, err := txmanager.NewDB(db).TxBegin()
+	if err != nil {
+		t.Fatalf("[error] begin: %v", err)
+	}
+	defer tx.TxFinish()
+
+	fl := FixtureLoader{driver: PostgreSQL, table: "item", bulkInsert: true}
+	var result LoadResult
+	if err := fl.execCopy(tx, data.columns, &countingSource{rowSource: newDataSource(data)}, &result); err != nil {
+		t.Fatalf("[error] exec copy: %v", err)
+	}
+
+	// empty values are left out of COPY to be loaded as DEFAULT
+	expect := []string{
+		`COPY "item" ("id", "name") FROM STDIN[1 Excalibur]`,
+		`COPY "item" ("id", "name") FROM STDIN[2 Masamune]`,
+		`COPY "item" ("id", "name") FROM STDIN[]`,
+		`COPY "item" ("name") FROM STDIN[Muramasa]`,
+		`COPY "item" ("name") FROM STDIN[]`,
+		`INSERT INTO "item" DEFAULT VALUES[]`,
+		`COPY "item" ("id", "name") FROM STDIN[5 Durandal]`,
+		`COPY "item" ("id", "name") FROM STDIN[]`,
+	}
+	if !reflect.DeepEqual(recorder.execs, expect) {
+		t.Fatalf("[error] exec copy: expect: %v but %v", expect, recorder.execs)
+	}
+
+	if result.RowsInserted != 5 || result.Statements != 4 {
+		t.Fatalf("[error] exec copy: expect: 5 rows by 4 statements but %+v", result)
+	}
+}
+
+func TestCopyColumns(t *testing.T) {
+	columns := []string{"id", "name", "price"}
+	row := map[string]string{"id": "1", "name": "", "price": "100"}
+
+	if present := copyColumns(columns, row); !reflect.DeepEqual(present, []string{"id", "price"}) {
+		t.Fatalf("[error] copy columns: expect: %v but %v", []string{"id", "price"}, present)
+	}
+}
@@ -3,48 +3,153 @@ package loader
 import (
 	"encoding/csv"
 	"io"
-	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-func (fx FixtureLoader) getDataFromCSV(file, format string) (Data, error) {
-	f, err := os.Open(file)
+// ColumnMismatch is how to handle csv rows whose number of fields differs from the header.
+// MismatchPad and MismatchIgnoreExtra can be combined.
+type ColumnMismatch int
+
+const (
+	// MismatchStrict returns error for rows with wrong number of fields
+	MismatchStrict ColumnMismatch = 0
+	// MismatchPad loads missing fields of short rows as DEFAULT
+	MismatchPad ColumnMismatch = 1 << iota
+	// MismatchIgnoreExtra ignores extra fields of long rows
+	MismatchIgnoreExtra
+)
+
+// CSVDialect is the dialect of csv files. A byte order mark at the beginning of file is always removed.
+type CSVDialect struct {
+	// Comma is the field delimiter. Default is ',', and '\t' for tsv
+	Comma rune
+	// Comment is the comment character. Lines beginning with it are ignored
+	Comment rune
+	// LazyQuotes allows a quote in an unquoted field and a non-doubled quote in a quoted field
+	LazyQuotes bool
+	// TrimLeadingSpace ignores leading white space of fields
+	TrimLeadingSpace bool
+	// Columns is the column names of files without header.
+	// When it is set, the first line is read as a row.
+	Columns []string
+}
+
+// csvSource is rowSource which reads csv file row by row
+type csvSource struct {
+	file     string
+	f        io.ReadCloser
+	reader   *csv.Reader
+	header   []string
+	mismatch ColumnMismatch
+}
+
+// newCSVSource returns csvSource which reads f. file is the name used in errors.
+func (fx FixtureLoader) newCSVSource(file string, f io.ReadCloser, format string) (*csvSource, error) {
+	decoded, err := fx.decodeReader(f)
 	if err != nil {
-		err = errors.Wrapf(err, "file: %s open error", file)
-		return Data{}, err
+		f.Close()
+		return nil, err
 	}
-	defer f.Close()
 
-	reader := csv.NewReader(f)
+	dialect := fx.csvDialect
+	reader := csv.NewReader(decoded)
 	if format == "tsv" {
 		reader.Comma = '\t'
 	}
+	if dialect.Comma != 0 {
+		reader.Comma = dialect.Comma
+	}
+	reader.Comment = dialect.Comment
+	reader.LazyQuotes = dialect.LazyQuotes
+	reader.TrimLeadingSpace = dialect.TrimLeadingSpace
+	reader.ReuseRecord = true
+	// the number of fields is checked by csvSource
+	reader.FieldsPerRecord = -1
 
-	columns, err := reader.Read()
-	if err != nil {
-		err = errors.Wrapf(err, "file: %s read error", file)
-		return Data{}, err
+	columns := dialect.Columns
+	if len(columns) == 0 {
+		columns, err = reader.Read()
+		if err != nil {
+			f.Close()
+			err = errors.Wrapf(err, "file: %s read error", file)
+			return nil, err
+		}
 	}
 
-	data := Data{columns: columns}
-	for {
-		row, err := reader.Read()
+	if err := validateHeader(columns); err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "file: %s", file)
+	}
 
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return data, err
+	return &csvSource{
+		file:     file,
+		f:        f,
+		reader:   reader,
+		header:   append([]string(nil), columns...),
+		mismatch: fx.csvMismatch,
+	}, nil
+}
+
+// validateHeader checks blank and duplicate column names
+func validateHeader(columns []string) error {
+	seen := make(map[string]bool, len(columns))
+	for i, column := range columns {
+		if strings.TrimSpace(column) == "" {
+			return errors.Wrapf(ErrMalformedFixture, "column %d of header is blank", i+1)
+		}
+		if seen[column] {
+			return errors.Wrapf(ErrMalformedFixture, "column %s of header is duplicated", column)
+		}
+		seen[column] = true
+	}
+
+	return nil
+}
+
+func (s *csvSource) columns() []string {
+	return s.header
+}
+
+func (s *csvSource) next() (map[string]string, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
 		}
+		return nil, errors.Wrapf(err, "file: %s read error", s.file)
+	}
 
-		rows := make(map[string]string, len(row))
-		for i, value := range row {
-			rows[data.columns[i]] = value
+	if len(record) > len(s.header) {
+		if s.mismatch&MismatchIgnoreExtra == 0 {
+			return nil, s.mismatchError(len(record))
 		}
+		record = record[:len(s.header)]
+	}
 
-		data.rows = append(data.rows, rows)
+	if len(record) < len(s.header) && s.mismatch&MismatchPad == 0 {
+		return nil, s.mismatchError(len(record))
 	}
 
-	return data, nil
+	// missing fields are not set, and loaded as DEFAULT
+	row := make(map[string]string, len(record))
+	for i, value := range record {
+		row[s.header[i]] = value
+	}
+
+	return row, nil
+}
+
+func (s *csvSource) mismatchError(fields int) error {
+	return errors.Wrapf(ErrMalformedFixture, "file: %s line: %d: %d fields but header has %d columns", s.file, s.position(), fields, len(s.header))
+}
+
+func (s *csvSource) position() int {
+	line, _ := s.reader.FieldPos(0)
+	return line
+}
+
+func (s *csvSource) close() error {
+	return s.f.Close()
 }
@@ -3,6 +3,8 @@ package loader
 import (
 	"reflect"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestGetDataFromCSV(t *testing.T) {
@@ -12,7 +14,7 @@ func TestGetDataFromCSV(t *testing.T) {
 
 	t.Run("load csv", func(t *testing.T) {
 		file := "_data/item.csv"
-		data, err := fx.getDataFromCSV(file, "csv")
+		data, err := readTestFile(fx, "csv", file)
 		if err != nil {
 			t.Fatalf("[error] get data from csv: %v", err)
 		}
@@ -24,11 +26,15 @@ func TestGetDataFromCSV(t *testing.T) {
 		if !reflect.DeepEqual(data.rows, rows) {
 			t.Fatalf("[error] get data from csv: expect: %v but %v", data.rows, rows)
 		}
+
+		if !reflect.DeepEqual(data.lines, []int{2, 3}) {
+			t.Fatalf("[error] get data from csv: expect: %v but %v", []int{2, 3}, data.lines)
+		}
 	})
 
 	t.Run("load empty csv", func(t *testing.T) {
 		file := "_data/zero.csv"
-		data, err := fx.getDataFromCSV(file, "csv")
+		data, err := readTestFile(fx, "csv", file)
 		if err != nil {
 			t.Fatalf("[error] get data from csv: %v", err)
 		}
@@ -44,7 +50,7 @@ func TestGetDataFromCSV(t *testing.T) {
 
 	t.Run("load tsv", func(t *testing.T) {
 		file := "_data/item.tsv"
-		data, err := fx.getDataFromCSV(file, "tsv")
+		data, err := readTestFile(fx, "tsv", file)
 		if err != nil {
 			t.Fatalf("[error] get data from csv: %v", err)
 		}
@@ -57,4 +63,83 @@ func TestGetDataFromCSV(t *testing.T) {
 			t.Fatalf("[error] get data from csv: expect: %v but %v", data.rows, rows)
 		}
 	})
+
+	t.Run("load csv with mismatched columns", func(t *testing.T) {
+		type Test struct {
+			Title    string
+			Mismatch ColumnMismatch
+			Output   []map[string]string
+		}
+
+		tests := []Test{
+			Test{Title: "strict", Mismatch: MismatchStrict},
+			Test{Title: "pad", Mismatch: MismatchPad},
+			Test{Title: "ignore extra", Mismatch: MismatchIgnoreExtra},
+			Test{
+				Title:    "pad and ignore extra",
+				Mismatch: MismatchPad | MismatchIgnoreExtra,
+				Output: []map[string]string{
+					map[string]string{"id": "1", "name": "item1"},
+					map[string]string{"id": "2"},
+					map[string]string{"id": "3", "name": "item3"},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Title, func(t *testing.T) {
+				fx := FixtureLoader{csvMismatch: test.Mismatch}
+				data, err := readTestFile(fx, "csv", "_data/mismatch.csv")
+				if test.Output == nil {
+					if !errors.Is(err, ErrMalformedFixture) {
+						t.Fatalf("[error] get data from csv: expect: %v but %v", ErrMalformedFixture, err)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("[error] get data from csv: %v", err)
+				}
+
+				if !reflect.DeepEqual(data.rows, test.Output) {
+					t.Fatalf("[error] get data from csv: expect: %v but %v", test.Output, data.rows)
+				}
+			})
+		}
+	})
+
+	t.Run("load csv with invalid header", func(t *testing.T) {
+		for _, file := range []string{
+			"_data/malformed/duplicate_header.csv",
+			"_data/malformed/blank_header.csv",
+		} {
+			_, err := readTestFile(fx, "csv", file)
+			if !errors.Is(err, ErrMalformedFixture) {
+				t.Fatalf("[error] get data from csv %s: expect: %v but %v", file, ErrMalformedFixture, err)
+			}
+		}
+	})
+
+	t.Run("load csv with dialect", func(t *testing.T) {
+		fx := FixtureLoader{csvDialect: CSVDialect{
+			Comma:            ';',
+			Comment:          '#',
+			LazyQuotes:       true,
+			TrimLeadingSpace: true,
+			Columns:          columns,
+		}}
+		data, err := readTestFile(fx, "csv", "_data/dialect.csv")
+		if err != nil {
+			t.Fatalf("[error] get data from csv: %v", err)
+		}
+
+		expect := []map[string]string{map[string]string{"id": "1", "name": "エクスカリバー"}, map[string]string{"id": "2", "name": "村\"正"}}
+		if !reflect.DeepEqual(data.rows, expect) {
+			t.Fatalf("[error] get data from csv: expect: %v but %v", expect, data.rows)
+		}
+
+		if !reflect.DeepEqual(data.lines, []int{2, 4}) {
+			t.Fatalf("[error] get data from csv: expect: %v but %v", []int{2, 4}, data.lines)
+		}
+	})
 }
@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	mysqlColumnQuery = `SELECT COLUMN_NAME FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`
+
+	postgresColumnQuery = `SELECT column_name FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1`
+)
+
+// mergeDefaults returns a copy of defaults with values
+func mergeDefaults(defaults map[string]string, values map[string]interface{}) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(values))
+	for column, value := range defaults {
+		merged[column] = value
+	}
+	for column, value := range values {
+		merged[column] = transformValue(value)
+	}
+
+	return merged
+}
+
+// columnDefaults returns default values of the columns of f.table whose fixture has columns.
+// Defaults of columns not in the fixture are used only when the table has the columns,
+// while TableDefaults are always used.
+func (f FixtureLoader) columnDefaults(columns []string) (map[string]string, error) {
+	tableDefaults := f.tableDefaults[f.table]
+	defaults := make(map[string]string, len(f.defaults)+len(tableDefaults))
+
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+
+	missing := false
+	for column, value := range f.defaults {
+		if known[column] {
+			defaults[column] = value
+		} else {
+			missing = true
+		}
+	}
+
+	if missing {
+		tableColumns, err := f.tableColumns()
+		if err != nil {
+			return nil, err
+		}
+		for column, value := range f.defaults {
+			if tableColumns[column] {
+				defaults[column] = value
+			}
+		}
+	}
+
+	for column, value := range tableDefaults {
+		defaults[column] = value
+	}
+
+	return defaults, nil
+}
+
+// tableColumns returns the columns of f.table.
+// Drivers other than mysql and postgres have no columns.
+func (f FixtureLoader) tableColumns() (map[string]bool, error) {
+	var query string
+	switch f.driver {
+	case MySQL:
+		query = mysqlColumnQuery
+	case PostgreSQL:
+		query = postgresColumnQuery
+	default:
+		return map[string]bool{}, nil
+	}
+
+	rows, err := f.txManager.Query(query, f.table)
+	if err != nil {
+		return nil, errors.Wrapf(err, "table: %s select columns error", f.table)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, errors.Wrapf(err, "table: %s select columns error", f.table)
+		}
+		columns[column] = true
+	}
+
+	return columns, rows.Err()
+}
+
+// defaultsSource is rowSource which fills missing or empty cells with default values.
+// Columns which are not in the fixture are appended in name order.
+type defaultsSource struct {
+	rowSource
+	header   []string
+	defaults map[string]string
+}
+
+func newDefaultsSource(src rowSource, defaults map[string]string) *defaultsSource {
+	columns := src.columns()
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+
+	added := make([]string, 0, len(defaults))
+	for column := range defaults {
+		if !known[column] {
+			added = append(added, column)
+		}
+	}
+	sort.Strings(added)
+
+	return &defaultsSource{
+		rowSource: src,
+		header:    append(append(make([]string, 0, len(columns)+len(added)), columns...), added...),
+		defaults:  defaults,
+	}
+}
+
+func (s *defaultsSource) columns() []string {
+	return s.header
+}
+
+func (s *defaultsSource) next() (map[string]string, error) {
+	row, err := s.rowSource.next()
+	if err != nil {
+		return nil, err
+	}
+
+	filled := make(map[string]string, len(s.header))
+	for column, value := range row {
+		filled[column] = value
+	}
+	for column, value := range s.defaults {
+		if filled[column] == "" {
+			filled[column] = value
+		}
+	}
+
+	return filled, nil
+}
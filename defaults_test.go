@@ -0,0 +1,128 @@
+package loader
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColumnDefaults(t *testing.T) {
+	f := FixtureLoader{table: "item"}
+	for _, option := range []Option{
+		Defaults(map[string]interface{}{"status": "active", "created_at": time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)}),
+		TableDefaults("item", map[string]interface{}{"status": "sealed"}),
+		TableDefaults("player", map[string]interface{}{"level": 1}),
+	} {
+		if err := option(&f); err != nil {
+			t.Fatalf("[error] option: %v", err)
+		}
+	}
+
+	type Test struct {
+		Title   string
+		Table   string
+		Columns []string
+		Expect  map[string]string
+	}
+
+	tests := []Test{
+		Test{
+			Title:   "columns in fixture",
+			Table:   "item",
+			Columns: []string{"id", "status", "created_at"},
+			Expect:  map[string]string{"status": "sealed", "created_at": "2019-01-02T00:00:00"},
+		},
+		// the driver has no columns of the table, so only TableDefaults add columns
+		Test{
+			Title:   "columns not in fixture",
+			Table:   "player",
+			Columns: []string{"id", "name"},
+			Expect:  map[string]string{"level": "1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			g := f
+			g.table = test.Table
+			defaults, err := g.columnDefaults(test.Columns)
+			if err != nil {
+				t.Fatalf("[error] column defaults: %v", err)
+			}
+			if !reflect.DeepEqual(defaults, test.Expect) {
+				t.Fatalf("[error] column defaults: expect: %v but %v", test.Expect, defaults)
+			}
+		})
+	}
+}
+
+func TestDefaultsSource(t *testing.T) {
+	data := Data{
+		columns: []string{"id", "name", "status"},
+		rows: []map[string]string{
+			map[string]string{"id": "1", "name": "エクスカリバー", "status": ""},
+			map[string]string{"id": "2", "name": "村正", "status": "sealed"},
+			map[string]string{"id": "3", "name": ""},
+		},
+	}
+
+	src := newDefaultsSource(newDataSource(data), map[string]string{"status": "active", "tenant_id": "1", "name": "unknown"})
+	filled, err := readAll(src)
+	if err != nil {
+		t.Fatalf("[error] read defaults source: %v", err)
+	}
+
+	columns := []string{"id", "name", "status", "tenant_id"}
+	if !reflect.DeepEqual(filled.columns, columns) {
+		t.Fatalf("[error] defaults columns: expect: %v but %v", columns, filled.columns)
+	}
+
+	rows := []map[string]string{
+		map[string]string{"id": "1", "name": "エクスカリバー", "status": "active", "tenant_id": "1"},
+		map[string]string{"id": "2", "name": "村正", "status": "sealed", "tenant_id": "1"},
+		map[string]string{"id": "3", "name": "unknown", "status": "active", "tenant_id": "1"},
+	}
+	if !reflect.DeepEqual(filled.rows, rows) {
+		t.Fatalf("[error] defaults rows: expect: %v but %v", rows, filled.rows)
+	}
+}
+
+func TestLoadFixtureDefaultsDB(t *testing.T) {
+	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("[error] db connection", err.Error())
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		"CREATE TABLE defaults_item (id INTEGER PRIMARY KEY, name VARCHAR(255), tenant_id INTEGER NOT NULL) DEFAULT CHARACTER SET utf8mb4",
+		"CREATE TABLE defaults_log (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4",
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal("[error] create table", err.Error())
+		}
+	}
+	defer db.Exec("DROP TABLE defaults_item, defaults_log")
+
+	fl, err := New(db, MySQL)
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	// tenant_id is added only to the table which has the column
+	for _, table := range []string{"defaults_item", "defaults_log"} {
+		err := fl.LoadFixture("_data/item.csv", Table(table), Defaults(map[string]interface{}{"tenant_id": 1}))
+		if err != nil {
+			t.Fatal("[error] load fixture:", err.Error())
+		}
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM defaults_item WHERE tenant_id = 1").Scan(&count); err != nil {
+		t.Fatal("[error] select error:", err.Error())
+	}
+	if count != 2 {
+		t.Fatalf("error load defaults. want:%d got:%d", 2, count)
+	}
+}
@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	mysqlForeignKeyQuery = `SELECT TABLE_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL`
+
+	postgresForeignKeyQuery = `SELECT tc.table_name, ccu.table_name FROM information_schema.table_constraints tc
+JOIN information_schema.constraint_column_usage ccu
+ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema
+WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema()`
+)
+
+// foreignKeys returns tables referenced by each table.
+// Drivers other than mysql and postgres have no dependency.
+func (f FixtureLoader) foreignKeys() (map[string][]string, error) {
+	var query string
+	switch f.driver {
+	case MySQL:
+		query = mysqlForeignKeyQuery
+	case PostgreSQL:
+		query = postgresForeignKeyQuery
+	default:
+		return map[string][]string{}, nil
+	}
+
+	rows, err := f.txManager.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "select foreign keys error")
+	}
+	defer rows.Close()
+
+	deps := map[string][]string{}
+	for rows.Next() {
+		var table, referenced string
+		if err := rows.Scan(&table, &referenced); err != nil {
+			return nil, errors.Wrap(err, "scan foreign keys error")
+		}
+		// self reference doesn't affect the order
+		if table != referenced {
+			deps[table] = append(deps[table], referenced)
+		}
+	}
+
+	return deps, rows.Err()
+}
+
+// sortFixtures sorts fixtures so that referenced tables are loaded first.
+// The original order is kept as much as possible, and fixtures in a cycle stay in the original order.
+func sortFixtures(fixtures []fixture, deps map[string][]string) []fixture {
+	loading := map[string]bool{}
+	for _, fx := range fixtures {
+		loading[fx.table] = true
+	}
+
+	sorted := make([]fixture, 0, len(fixtures))
+	done := make([]bool, len(fixtures))
+	loaded := map[string]bool{}
+
+	for len(sorted) < len(fixtures) {
+		progress := false
+		for i, fx := range fixtures {
+			if done[i] || !ready(fx.table, deps, loading, loaded) {
+				continue
+			}
+			sorted = append(sorted, fx)
+			done[i] = true
+			progress = true
+		}
+
+		// circular dependency: load the rest in the original order
+		if !progress {
+			for i, fx := range fixtures {
+				if !done[i] {
+					sorted = append(sorted, fx)
+					done[i] = true
+				}
+			}
+		}
+
+		for _, fx := range sorted {
+			loaded[fx.table] = true
+		}
+	}
+
+	return sorted
+}
+
+// ready reports whether all tables referenced by table are already loaded
+func ready(table string, deps map[string][]string, loading, loaded map[string]bool) bool {
+	for _, dep := range deps[table] {
+		if loading[dep] && !loaded[dep] && dep != table {
+			return false
+		}
+	}
+
+	return true
+}
+
+// groupFixtures splits fixtures into groups which have no foreign key dependency on each other.
+// Each group is sorted by sortFixtures.
+func groupFixtures(fixtures []fixture, deps map[string][]string) [][]fixture {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(t string) string {
+		if p, ok := parent[t]; ok && p != t {
+			parent[t] = find(p)
+			return parent[t]
+		}
+		parent[t] = t
+		return t
+	}
+
+	loading := map[string]bool{}
+	for _, fx := range fixtures {
+		loading[fx.table] = true
+		find(fx.table)
+	}
+
+	for table, refs := range deps {
+		if !loading[table] {
+			continue
+		}
+		for _, ref := range refs {
+			if loading[ref] {
+				parent[find(table)] = find(ref)
+			}
+		}
+	}
+
+	index := map[string]int{}
+	var groups [][]fixture
+	for _, fx := range fixtures {
+		root := find(fx.table)
+		i, ok := index[root]
+		if !ok {
+			i = len(groups)
+			index[root] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], fx)
+	}
+
+	for i := range groups {
+		groups[i] = sortFixtures(groups[i], deps)
+	}
+
+	return groups
+}
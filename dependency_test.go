@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortFixtures(t *testing.T) {
+	type Test struct {
+		Title  string
+		Input  []fixture
+		Deps   map[string][]string
+		Output []string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "no dependency keeps order",
+			Input:  []fixture{fixture{table: "b"}, fixture{table: "a"}},
+			Deps:   map[string][]string{},
+			Output: []string{"b", "a"},
+		},
+		Test{
+			Title:  "referenced table first",
+			Input:  []fixture{fixture{table: "player_item"}, fixture{table: "guild"}, fixture{table: "player"}},
+			Deps:   map[string][]string{"player_item": []string{"player"}},
+			Output: []string{"guild", "player", "player_item"},
+		},
+		Test{
+			Title:  "dependency on table not loaded is ignored",
+			Input:  []fixture{fixture{table: "player_item"}},
+			Deps:   map[string][]string{"player_item": []string{"player"}},
+			Output: []string{"player_item"},
+		},
+		Test{
+			Title:  "circular dependency keeps order",
+			Input:  []fixture{fixture{table: "a"}, fixture{table: "b"}, fixture{table: "c"}},
+			Deps:   map[string][]string{"a": []string{"b"}, "b": []string{"a"}},
+			Output: []string{"c", "a", "b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			sorted := sortFixtures(test.Input, test.Deps)
+			tables := make([]string, 0, len(sorted))
+			for _, fx := range sorted {
+				tables = append(tables, fx.table)
+			}
+
+			if !reflect.DeepEqual(tables, test.Output) {
+				t.Fatalf("[error] sort fixtures: expect: %v but %v", test.Output, tables)
+			}
+		})
+	}
+}
+
+func TestGroupFixtures(t *testing.T) {
+	fixtures := []fixture{
+		fixture{table: "player_item"},
+		fixture{table: "guild"},
+		fixture{table: "player"},
+	}
+	deps := map[string][]string{"player_item": []string{"player"}}
+
+	groups := groupFixtures(fixtures, deps)
+	tables := [][]string{}
+	for _, group := range groups {
+		g := []string{}
+		for _, fx := range group {
+			g = append(g, fx.table)
+		}
+		tables = append(tables, g)
+	}
+
+	expect := [][]string{[]string{"player", "player_item"}, []string{"guild"}}
+	if !reflect.DeepEqual(tables, expect) {
+		t.Fatalf("[error] group fixtures: expect: %v but %v", expect, tables)
+	}
+}
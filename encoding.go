@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// encodingAliases is names which are not in WHATWG encoding labels
+var encodingAliases = map[string]string{
+	"cp932": "windows-31j",
+	"eucjp": "euc-jp",
+	"utf16": "utf-16le",
+}
+
+// lookupEncoding returns encoding by the name like "shift_jis", "cp932" and "euc-jp"
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	label := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := encodingAliases[label]; ok {
+		label = alias
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unknown encoding: %s", name)
+	}
+
+	return enc, nil
+}
+
+// decodeReader returns reader which transcodes r to UTF-8.
+// UTF-8 and UTF-16 byte order marks are detected and removed, and they take priority over `encoding` option.
+func (fx FixtureLoader) decodeReader(r io.Reader) (io.Reader, error) {
+	fallback := encoding.Nop.NewDecoder()
+	if fx.encoding != "" {
+		enc, err := lookupEncoding(fx.encoding)
+		if err != nil {
+			return nil, err
+		}
+		fallback = enc.NewDecoder()
+	}
+
+	return transform.NewReader(r, unicode.BOMOverride(fallback)), nil
+}
@@ -0,0 +1,55 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetDataFromEncodedCSV(t *testing.T) {
+	columns := []string{"id", "name"}
+	rows := []map[string]string{map[string]string{"id": "1", "name": "エクスカリバー"}, map[string]string{"id": "2", "name": "村正"}}
+
+	type Test struct {
+		Title    string
+		File     string
+		Encoding string
+	}
+
+	tests := []Test{
+		Test{Title: "shift_jis", File: "_data/item_sjis.csv", Encoding: "shift_jis"},
+		Test{Title: "cp932", File: "_data/item_sjis.csv", Encoding: "cp932"},
+		Test{Title: "utf-8 with bom", File: "_data/item_bom.csv"},
+		Test{Title: "utf-16 with bom", File: "_data/item_utf16.csv"},
+		Test{Title: "bom takes priority over encoding", File: "_data/item_bom.csv", Encoding: "shift_jis"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			fx := FixtureLoader{encoding: test.Encoding}
+			data, err := readTestFile(fx, "csv", test.File)
+			if err != nil {
+				t.Fatalf("[error] get data from csv: %v", err)
+			}
+
+			if !reflect.DeepEqual(data.columns, columns) {
+				t.Fatalf("[error] get data from csv: expect: %v but %v", columns, data.columns)
+			}
+
+			if !reflect.DeepEqual(data.rows, rows) {
+				t.Fatalf("[error] get data from csv: expect: %v but %v", rows, data.rows)
+			}
+		})
+	}
+}
+
+func TestLookupEncoding(t *testing.T) {
+	for _, name := range []string{"shift_jis", "Shift_JIS", "cp932", "euc-jp", "utf-8"} {
+		if _, err := lookupEncoding(name); err != nil {
+			t.Fatalf("[error] lookup encoding %s: %v", name, err)
+		}
+	}
+
+	if _, err := lookupEncoding("unknown"); err == nil {
+		t.Fatal("[error] lookup unknown encoding must be error")
+	}
+}
@@ -0,0 +1,46 @@
+package loader
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedValue is returned when the fixture value is neither file name nor Data
+	ErrUnsupportedValue = errors.New("unsupported fixture value")
+	// ErrUnsupportedFormat is returned when the file format is not supported
+	ErrUnsupportedFormat = errors.New("unsupported fixture format")
+	// ErrInvalidFileName is returned when table name can't be taken from the file name
+	ErrInvalidFileName = errors.New("invalid fixture file name")
+	// ErrMalformedFixture is returned when the fixture can't be parsed
+	ErrMalformedFixture = errors.New("malformed fixture")
+	// ErrEmptyFixture is returned when the fixture has no rows where rows are required
+	ErrEmptyFixture = errors.New("empty fixture")
+)
+
+// RowError is an error caused by one row of fixture.
+// It doesn't implement Cause, so errors.Cause returns *RowError.
+type RowError struct {
+	File  string
+	Line  int // line (or record) number of the row
+	Table string
+	Row   map[string]string
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("file: %s line: %d table: %s row: %v: %s", e.File, e.Line, e.Table, e.Row, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// setErrorFile sets file to RowError in err
+func setErrorFile(err error, file string) {
+	if re, ok := errors.Cause(err).(*RowError); ok && re.File == "" {
+		re.File = file
+	}
+}
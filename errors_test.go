@@ -0,0 +1,107 @@
+package loader
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRowError(t *testing.T) {
+	cause := errors.New("duplicate entry")
+	err := errors.Wrap(&RowError{File: "item.csv", Line: 3, Table: "item", Row: map[string]string{"id": "1"}, Err: cause}, "db insert error")
+
+	expect := "db insert error: file: item.csv line: 3 table: item row: map[id:1]: duplicate entry"
+	if err.Error() != expect {
+		t.Fatalf("[error] row error: expect: %s but %s", expect, err.Error())
+	}
+
+	rowErr, ok := errors.Cause(err).(*RowError)
+	if !ok {
+		t.Fatalf("[error] row error cause must be RowError: %v", errors.Cause(err))
+	}
+
+	if rowErr.Unwrap() != cause {
+		t.Fatalf("[error] row error unwrap: expect: %v but %v", cause, rowErr.Unwrap())
+	}
+}
+
+func TestLoadFixtureError(t *testing.T) {
+	fl, err := New(nil, MySQL)
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	type Test struct {
+		Title  string
+		Input  interface{}
+		Output error
+	}
+
+	tests := []Test{
+		Test{Title: "not file name", Input: 1, Output: ErrUnsupportedValue},
+		Test{Title: "unsupported format", Input: "_data/malformed/item.txt", Output: ErrUnsupportedFormat},
+		Test{Title: "no extension", Input: "_data/item", Output: ErrUnsupportedFormat},
+		Test{Title: "invalid file name", Input: "_data/-item.csv", Output: ErrInvalidFileName},
+		Test{Title: "malformed fixture", Input: "_data/malformed/map.json", Output: ErrMalformedFixture},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			err := fl.LoadFixture(test.Input)
+			if !errors.Is(err, test.Output) {
+				t.Fatalf("[error] load fixture: expect: %v but %v", test.Output, err)
+			}
+		})
+	}
+}
+
+func TestLoadFixtureRowError(t *testing.T) {
+	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("[error] db connection", err.Error())
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE row_error_item (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4")
+	if err != nil {
+		t.Fatal("[error] create table", err.Error())
+	}
+	defer db.Exec("DROP TABLE row_error_item")
+
+	fl, err := New(db, MySQL, Table("row_error_item"))
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	type Test struct {
+		Title   string
+		Options []Option
+	}
+
+	tests := []Test{
+		Test{Title: "insert row by row"},
+		Test{Title: "bulk insert", Options: []Option{BulkInsert(true)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			err := fl.LoadFixture("_data/duplicate.csv", test.Options...)
+			rowErr, ok := errors.Cause(err).(*RowError)
+			if !ok {
+				t.Fatalf("[error] load fixture must be RowError: %v", err)
+			}
+
+			if rowErr.File != "_data/duplicate.csv" || rowErr.Line != 4 || rowErr.Table != "row_error_item" || rowErr.Row["name"] != "duplicated" {
+				t.Fatalf("[error] invalid row error: %+v", rowErr)
+			}
+		})
+	}
+
+	t.Run("delete error", func(t *testing.T) {
+		err := fl.LoadFixture("_data/zero.csv", Table("not_exists"), Delete(true))
+		if err == nil {
+			t.Fatal("[error] delete not existing table must be error")
+		}
+	})
+}
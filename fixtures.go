@@ -0,0 +1,265 @@
+package loader
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+// fixture is a file loaded into a table.
+// data is set for a fixture parsed before loading, and options are set for each file by manifest.
+type fixture struct {
+	file    string
+	name    string // file name in errors
+	script  bool   // sql script isn't loaded into the table
+	table   string
+	data    *Data
+	options []Option
+}
+
+// LoadFixtures loads multiple fixture files. A directory is expanded to the files in it.
+// The table of each file is taken from its file name, and tables are loaded in the order of
+// foreign key dependency. All tables are loaded in one transaction unless Parallelism is set.
+func (fl FixtureLoader) LoadFixtures(files []string, options ...Option) error {
+	_, err := fl.LoadFixturesWithResult(files, options...)
+	return err
+}
+
+// LoadFixturesWithResult is LoadFixtures and returns the result of each loaded table
+func (fl FixtureLoader) LoadFixturesWithResult(files []string, options ...Option) ([]LoadResult, error) {
+	f := fl
+	for _, option := range options {
+		if err := option(&f); err != nil {
+			return nil, errors.Wrap(err, "error invalid option")
+		}
+	}
+
+	fixtures, err := f.expandFixtures(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.loadFixtureSet(fixtures)
+}
+
+// loadFixtureSet loads fixtures in the order of foreign key dependency
+func (f FixtureLoader) loadFixtureSet(fixtures []fixture) ([]LoadResult, error) {
+	if f.parallelism > 1 && f.hasHooks(HookBatch) {
+		return nil, errors.New("error HookBatch can't be used with `parallelism`")
+	}
+	if f.parallelism > 1 {
+		// a script has no foreign keys to be ordered with the tables
+		for _, fx := range fixtures {
+			if fx.script {
+				return nil, errors.Errorf("error sql script %s can't be loaded with `parallelism`", fx.name)
+			}
+		}
+	}
+
+	f.packet = &packetSize{}
+	deps, err := f.foreignKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if f.parallelism > 1 {
+		return f.loadParallel(groupFixtures(fixtures, deps))
+	}
+
+	return f.loadBatch(func(f FixtureLoader) ([]LoadResult, error) {
+		return f.loadSerial(sortFixtures(fixtures, deps))
+	})
+}
+
+func (f FixtureLoader) expandFixtures(files []string) ([]fixture, error) {
+	// the table of each file is taken from its file name
+	f.table = ""
+	fixtures := make([]fixture, 0, len(files))
+	for _, file := range files {
+		paths, err := expandPath(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range paths {
+			fxs, err := f.fileFixtures(p)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, fxs...)
+		}
+	}
+
+	return fixtures, nil
+}
+
+// expandPath returns files in the directory, or file itself
+func expandPath(file string) ([]string, error) {
+	infos, err := ioutil.ReadDir(file)
+	if err != nil {
+		// not a directory
+		return []string{file}, nil
+	}
+
+	files := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		files = append(files, filepath.Join(file, info.Name()))
+	}
+
+	return files, nil
+}
+
+// loadSerial loads fixtures in one transaction in order
+func (f FixtureLoader) loadSerial(fixtures []fixture) ([]LoadResult, error) {
+	tx, err := f.txManager.TxBegin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.TxFinish()
+
+	results, err := f.withTx(tx).loadFixturesInTx(fixtures)
+	if err != nil {
+		tx.TxRollback()
+		return nil, err
+	}
+
+	if err := tx.TxCommit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// loadParallel loads each group of fixtures concurrently on separate connections.
+// Unless commitEach, connections are committed one by one only after every group succeeded.
+func (f FixtureLoader) loadParallel(groups [][]fixture) ([]LoadResult, error) {
+	type groupResult struct {
+		tx      txmanager.Tx
+		results []LoadResult
+		err     error
+	}
+
+	queue := make(chan int)
+	loaded := make([]groupResult, len(groups))
+
+	var wg sync.WaitGroup
+	for i := 0; i < f.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				tx, err := f.txManager.TxBegin()
+				if err != nil {
+					loaded[i] = groupResult{err: err}
+					continue
+				}
+
+				results, err := f.withTx(tx).loadFixturesInTx(groups[i])
+				if err == nil && f.commitEach {
+					err = tx.TxCommit()
+				}
+				if err != nil {
+					tx.TxRollback()
+				}
+				loaded[i] = groupResult{tx: tx, results: results, err: err}
+			}
+		}()
+	}
+
+	for i := range groups {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	var results []LoadResult
+	var firstErr error
+	for _, r := range loaded {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		results = append(results, r.results...)
+	}
+
+	if f.commitEach {
+		return results, firstErr
+	}
+
+	// commit only when all groups are loaded. Commits are sequential, so a failed commit
+	// rolls back the remaining groups but can't undo the groups already committed.
+	if firstErr != nil {
+		for _, r := range loaded {
+			if r.err == nil {
+				r.tx.TxRollback()
+			}
+		}
+		return nil, firstErr
+	}
+
+	for i, r := range loaded {
+		if err := r.tx.TxCommit(); err != nil {
+			for _, rest := range loaded[i+1:] {
+				rest.tx.TxRollback()
+			}
+			return nil, errors.Wrapf(err, "commit error: %d of %d groups are committed", i, len(loaded))
+		}
+	}
+
+	return results, nil
+}
+
+// loadFixturesInTx loads fixtures in order within f.txManager.
+// With delete option, tables are deleted in reverse order first not to violate foreign keys.
+func (f FixtureLoader) loadFixturesInTx(fixtures []fixture) ([]LoadResult, error) {
+	deleting := f.delete
+	deleted := make([]int64, len(fixtures))
+	if deleting {
+		for i := len(fixtures) - 1; i >= 0; i-- {
+			// sql script isn't loaded into the table of its file name
+			if fixtures[i].script {
+				continue
+			}
+			query, args, err := f.statementBuilder().Delete(quote(f.driver, fixtures[i].table)).ToSql()
+			if err != nil {
+				return nil, err
+			}
+			res, err := f.txManager.Exec(query, args...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "table: %s delete error", fixtures[i].table)
+			}
+			deleted[i], _ = res.RowsAffected()
+		}
+		f.delete = false
+	}
+
+	results := make([]LoadResult, 0, len(fixtures))
+	for i, fx := range fixtures {
+		result, err := f.loadFixture(fx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "file: %s load error", fx.name)
+		}
+		if deleting && !fx.script {
+			result.RowsDeleted = deleted[i]
+			result.Statements++
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// withTx returns FixtureLoader which begins nested transactions of tx
+func (f FixtureLoader) withTx(tx txmanager.Tx) FixtureLoader {
+	f.txManager = tx
+	return f
+}
@@ -0,0 +1,157 @@
+package loader
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/shogo82148/txmanager"
+)
+
+func TestExpandFixtures(t *testing.T) {
+	fixtures, err := FixtureLoader{}.expandFixtures([]string{"_data/fixtures", "_data/item.csv"})
+	if err != nil {
+		t.Fatalf("[error] expand fixtures: %v", err)
+	}
+
+	expect := []fixture{
+		fixture{file: "_data/fixtures/guild.csv", name: "_data/fixtures/guild.csv", table: "guild"},
+		fixture{file: "_data/fixtures/player.csv", name: "_data/fixtures/player.csv", table: "player"},
+		fixture{file: "_data/fixtures/player_item.csv", name: "_data/fixtures/player_item.csv", table: "player_item"},
+		fixture{file: "_data/item.csv", name: "_data/item.csv", table: "item"},
+	}
+	if !reflect.DeepEqual(fixtures, expect) {
+		t.Fatalf("[error] expand fixtures: expect: %v but %v", expect, fixtures)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("[error] db connection", err.Error())
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		"CREATE TABLE guild (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4",
+		"CREATE TABLE player (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4",
+		"CREATE TABLE player_item (id INTEGER PRIMARY KEY, player_id INTEGER, name VARCHAR(255), FOREIGN KEY (player_id) REFERENCES player (id)) DEFAULT CHARACTER SET utf8mb4",
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal("[error] create table", err.Error())
+		}
+	}
+	defer db.Exec("DROP TABLE player_item, player, guild")
+
+	type Test struct {
+		Title   string
+		Options []Option
+	}
+
+	tests := []Test{
+		Test{
+			Title:   "load fixtures in one transaction",
+			Options: []Option{Delete(true)},
+		},
+		Test{
+			Title:   "load fixtures in parallel",
+			Options: []Option{Delete(true), Parallelism(2)},
+		},
+		Test{
+			Title:   "load fixtures in parallel committing each connection",
+			Options: []Option{Delete(true), Parallelism(2), DeferCommit(false)},
+		},
+	}
+
+	fl, err := New(db, MySQL)
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			if err := fl.LoadFixtures([]string{"_data/fixtures"}, test.Options...); err != nil {
+				t.Fatal("[error] load fixtures:", err.Error())
+			}
+
+			for table, want := range map[string]int{"guild": 1, "player": 2, "player_item": 2} {
+				var count int
+				if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+					t.Fatal("[error] select error:", err.Error())
+				}
+
+				if count != want {
+					t.Fatalf("error load data %s. want:%d got:%d", table, want, count)
+				}
+			}
+		})
+	}
+
+	t.Run("load archive", func(t *testing.T) {
+		for _, archive := range []string{"_data/fixtures.zip", "_data/fixtures.tar.gz"} {
+			results, err := fl.LoadArchiveWithResult(archive, Delete(true))
+			if err != nil {
+				t.Fatal("[error] load archive:", err.Error())
+			}
+
+			tables := make([]string, 0, len(results))
+			for _, result := range results {
+				tables = append(tables, result.Table)
+			}
+			if !reflect.DeepEqual(tables, []string{"guild", "player", "player_item"}) {
+				t.Fatalf("error load archive order. got:%v", tables)
+			}
+		}
+	})
+
+	t.Run("run batch hooks of archive", func(t *testing.T) {
+		called := 0
+		err := fl.LoadArchive("_data/fixtures.zip", Delete(true), AfterLoad(HookBatch, func(tx txmanager.Tx, table string) error {
+			called++
+			return nil
+		}))
+		if err != nil {
+			t.Fatal("[error] load archive:", err.Error())
+		}
+		if called != 1 {
+			t.Fatalf("error run batch hooks. want:%d got:%d", 1, called)
+		}
+	})
+
+	t.Run("load manifest", func(t *testing.T) {
+		results, err := fl.LoadManifestWithResult("_data/manifest/manifest.yaml", Delete(true))
+		if err != nil {
+			t.Fatal("[error] load manifest:", err.Error())
+		}
+
+		for _, result := range results {
+			if result.RowsRead == 0 {
+				t.Fatalf("error load manifest %s. got:%v", result.Table, result)
+			}
+		}
+
+		var name string
+		if err := db.QueryRow("SELECT name FROM guild WHERE id = 1").Scan(&name); err != nil {
+			t.Fatal("[error] select error:", err.Error())
+		}
+		if name != "Round Table" {
+			t.Fatalf("error load manifest. want:%s got:%s", "Round Table", name)
+		}
+	})
+
+	t.Run("rollback all tables on error", func(t *testing.T) {
+		err := fl.LoadFixtures([]string{"_data/fixtures", "_data/item_update.csv"}, Delete(true), Parallelism(2))
+		if err == nil {
+			t.Fatal("[error] load fixtures into not existing table must be error")
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM player").Scan(&count); err != nil {
+			t.Fatal("[error] select error:", err.Error())
+		}
+
+		if count != 2 {
+			t.Fatalf("error rollback data. want:%d got:%d", 2, count)
+		}
+	})
+}
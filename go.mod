@@ -1,12 +1,17 @@
 module github.com/Konboi/go-fixture-loader
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/Masterminds/squirrel v1.1.0
 	github.com/go-sql-driver/mysql v1.4.1
+	github.com/klauspost/compress v1.15.9
 	github.com/lestrrat-go/tcputil v0.0.0-20180223003554-d3c7f98154fb // indirect
 	github.com/lestrrat-go/test-mysqld v0.0.0-20181002092724-b25618440bf6
-	github.com/pkg/errors v0.8.1
+	github.com/lib/pq v1.10.9
+	github.com/pkg/errors v0.9.1
 	github.com/shogo82148/txmanager v0.0.1
+	github.com/tealeg/xlsx v1.0.5
 	golang.org/x/lint v0.0.0-20190409202823-959b441ac422 // indirect
-	gopkg.in/yaml.v2 v2.2.2
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
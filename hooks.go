@@ -0,0 +1,145 @@
+package loader
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+// Hook is a function called in the transaction of loading.
+// table is the loading table for HookTable, and empty for HookBatch.
+type Hook func(tx txmanager.Tx, table string) error
+
+// HookScope is when a hook is called
+type HookScope int
+
+const (
+	// HookTable calls the hook for each table
+	HookTable HookScope = iota
+	// HookBatch calls the hook once for all tables loaded by one call of LoadFixture, LoadFixtures,
+	// LoadArchive or LoadManifest. A batch is such a call, not each bulk insert statement.
+	// It can't be used with Parallelism because tables are loaded in separate transactions.
+	HookBatch
+)
+
+// loadHook is a hook set by BeforeLoad or AfterLoad
+type loadHook struct {
+	scope HookScope
+	after bool
+	hook  Hook
+}
+
+// BeforeLoad set a hook called before loading in the same transaction.
+// Multiple hooks are called in order.
+func BeforeLoad(scope HookScope, hook Hook) Option {
+	return addHook(scope, false, hook)
+}
+
+// AfterLoad set a hook called after loading in the same transaction, before commit.
+// Multiple hooks are called in order.
+func AfterLoad(scope HookScope, hook Hook) Option {
+	return addHook(scope, true, hook)
+}
+
+// BeforeLoadSQL set queries executed before loading as BeforeLoad
+func BeforeLoadSQL(scope HookScope, queries ...string) Option {
+	return addHook(scope, false, sqlHook(queries))
+}
+
+// AfterLoadSQL set queries executed after loading as AfterLoad
+func AfterLoadSQL(scope HookScope, queries ...string) Option {
+	return addHook(scope, true, sqlHook(queries))
+}
+
+func addHook(scope HookScope, after bool, hook Hook) Option {
+	return func(f *FixtureLoader) error {
+		if scope != HookTable && scope != HookBatch {
+			return errors.Errorf("error unknown hook scope %d", scope)
+		}
+		if hook == nil {
+			return errors.New("error `hook` must not be nil")
+		}
+		hooks := make([]loadHook, 0, len(f.hooks)+1)
+		f.hooks = append(append(hooks, f.hooks...), loadHook{scope: scope, after: after, hook: hook})
+		return nil
+	}
+}
+
+// sqlHook returns Hook which executes queries
+func sqlHook(queries []string) Hook {
+	return func(tx txmanager.Tx, table string) error {
+		for _, query := range queries {
+			if _, err := tx.Exec(query); err != nil {
+				return errors.Wrapf(err, "query: %s", query)
+			}
+		}
+		return nil
+	}
+}
+
+// hasHooks reports whether hooks of scope are set
+func (f FixtureLoader) hasHooks(scope HookScope) bool {
+	for _, h := range f.hooks {
+		if h.scope == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runHooks calls hooks of scope before or after loading
+func (f FixtureLoader) runHooks(tx txmanager.Tx, scope HookScope, after bool) error {
+	table := f.table
+	if scope == HookBatch {
+		table = ""
+	}
+
+	for _, h := range f.hooks {
+		if h.scope != scope || h.after != after {
+			continue
+		}
+		if err := h.hook(tx, table); err != nil {
+			if after {
+				return errors.Wrapf(err, "table: %s after load hook error", table)
+			}
+			return errors.Wrapf(err, "table: %s before load hook error", table)
+		}
+	}
+
+	return nil
+}
+
+// loadBatch calls load between hooks of HookBatch in one transaction
+func (f FixtureLoader) loadBatch(load func(f FixtureLoader) ([]LoadResult, error)) ([]LoadResult, error) {
+	if !f.hasHooks(HookBatch) {
+		return load(f)
+	}
+
+	tx, err := f.txManager.TxBegin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.TxFinish()
+
+	if err := f.runHooks(tx, HookBatch, false); err != nil {
+		tx.TxRollback()
+		return nil, err
+	}
+
+	results, err := load(f.withTx(tx))
+	if err != nil {
+		tx.TxRollback()
+		return nil, err
+	}
+
+	if err := f.runHooks(tx, HookBatch, true); err != nil {
+		tx.TxRollback()
+		return nil, err
+	}
+
+	if err := tx.TxCommit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,139 @@
+package loader
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+func TestRunHooks(t *testing.T) {
+	var called []string
+	record := func(name string) Hook {
+		return func(tx txmanager.Tx, table string) error {
+			called = append(called, name+":"+table)
+			return nil
+		}
+	}
+
+	f := FixtureLoader{table: "item"}
+	for _, option := range []Option{
+		BeforeLoad(HookTable, record("before table")),
+		AfterLoad(HookTable, record("after table")),
+		BeforeLoad(HookBatch, record("before batch")),
+		AfterLoad(HookBatch, record("after batch")),
+		BeforeLoad(HookTable, record("before table 2")),
+	} {
+		if err := option(&f); err != nil {
+			t.Fatalf("[error] option: %v", err)
+		}
+	}
+
+	t.Run("run hooks of scope in order", func(t *testing.T) {
+		called = nil
+		if err := f.runHooks(nil, HookTable, false); err != nil {
+			t.Fatalf("[error] run hooks: %v", err)
+		}
+		if err := f.runHooks(nil, HookBatch, true); err != nil {
+			t.Fatalf("[error] run hooks: %v", err)
+		}
+
+		expect := []string{"before table:item", "before table 2:item", "after batch:"}
+		if !reflect.DeepEqual(called, expect) {
+			t.Fatalf("[error] run hooks: expect: %v but %v", expect, called)
+		}
+	})
+
+	t.Run("hook error", func(t *testing.T) {
+		errHook := errors.New("hook error")
+		g := f
+		if err := AfterLoad(HookTable, func(tx txmanager.Tx, table string) error { return errHook })(&g); err != nil {
+			t.Fatalf("[error] option: %v", err)
+		}
+
+		if err := g.runHooks(nil, HookTable, true); !errors.Is(err, errHook) {
+			t.Fatalf("[error] run hooks: expect: %v but %v", errHook, err)
+		}
+	})
+
+	t.Run("batch hooks with parallelism", func(t *testing.T) {
+		g := f
+		g.parallelism = 2
+		if _, err := g.loadFixtureSet(nil); err == nil {
+			t.Fatal("[error] load fixtures with batch hooks and parallelism must be error")
+		}
+	})
+
+	t.Run("load batch without hooks", func(t *testing.T) {
+		expect := []LoadResult{LoadResult{Table: "item"}}
+		results, err := FixtureLoader{}.loadBatch(func(f FixtureLoader) ([]LoadResult, error) {
+			return expect, nil
+		})
+		if err != nil || !reflect.DeepEqual(results, expect) {
+			t.Fatalf("[error] load batch: expect: %v but %v %v", expect, results, err)
+		}
+	})
+}
+
+func TestLoadFixtureHooksDB(t *testing.T) {
+	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("[error] db connection", err.Error())
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		"CREATE TABLE hook_item (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4",
+		"CREATE TABLE hook_summary (tables INTEGER, items INTEGER)",
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal("[error] create table", err.Error())
+		}
+	}
+	defer db.Exec("DROP TABLE hook_item, hook_summary")
+
+	fl, err := New(db, MySQL)
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	t.Run("run hooks in the transaction", func(t *testing.T) {
+		err := fl.LoadFixture("_data/item.csv", Table("hook_item"),
+			BeforeLoadSQL(HookBatch, "DELETE FROM hook_summary"),
+			AfterLoad(HookTable, func(tx txmanager.Tx, table string) error {
+				_, err := tx.Exec("INSERT INTO hook_summary (tables, items) SELECT 1, COUNT(*) FROM " + table)
+				return err
+			}),
+		)
+		if err != nil {
+			t.Fatal("[error] load fixture:", err.Error())
+		}
+
+		var tables, items int
+		if err := db.QueryRow("SELECT tables, items FROM hook_summary").Scan(&tables, &items); err != nil {
+			t.Fatal("[error] select error:", err.Error())
+		}
+		if tables != 1 || items != 2 {
+			t.Fatalf("error run hooks. want:1 2 got:%d %d", tables, items)
+		}
+	})
+
+	t.Run("rollback on hook error", func(t *testing.T) {
+		err := fl.LoadFixture("_data/item.csv", Table("hook_item"), Delete(true),
+			AfterLoadSQL(HookBatch, "INSERT INTO not_exist_table VALUES (1)"),
+		)
+		if err == nil {
+			t.Fatal("[error] load fixture with failing hook must be error")
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM hook_summary").Scan(&count); err != nil {
+			t.Fatal("[error] select error:", err.Error())
+		}
+		if count != 1 {
+			t.Fatalf("error rollback. want:%d got:%d", 1, count)
+		}
+	})
+}
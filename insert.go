@@ -0,0 +1,268 @@
+package loader
+
+import (
+	"database/sql"
+	"io"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+// maxPlaceholders is the limit of placeholders in one statement of mysql and postgres
+const maxPlaceholders = 65535
+
+// mysql error numbers which roll back the transaction
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrLockDeadlock    = 1213
+)
+
+// packetHeadroom is reserved in max_allowed_packet for the statement other than values
+const packetHeadroom = 1024
+
+// batchLimit is the limit of one bulk insert statement
+type batchLimit struct {
+	rows  int
+	bytes int // 0 is unlimited
+}
+
+// newBatchLimit returns the limit of one bulk insert statement.
+// bulkInsertLimit is the upper bound, and it is capped by the number of placeholders
+// and mysql's max_allowed_packet.
+func (f FixtureLoader) newBatchLimit(tx txmanager.Tx, columns int) (batchLimit, error) {
+	limit := batchLimit{rows: f.bulkInsertLimit}
+	if columns > 0 && limit.rows > maxPlaceholders/columns {
+		limit.rows = maxPlaceholders / columns
+	}
+
+	if f.driver != MySQL {
+		return limit, nil
+	}
+
+	packet, err := f.maxAllowedPacket(tx)
+	if err != nil {
+		return limit, err
+	}
+	limit.bytes = packet - packetHeadroom
+
+	return limit, nil
+}
+
+// packetSize is mysql's max_allowed_packet queried once for a load
+type packetSize struct {
+	once  sync.Once
+	bytes int
+	err   error
+}
+
+// maxAllowedPacket returns max_allowed_packet, which is cached in f.packet for the load
+func (f FixtureLoader) maxAllowedPacket(tx txmanager.Tx) (int, error) {
+	packet := f.packet
+	if packet == nil {
+		packet = &packetSize{}
+	}
+
+	packet.once.Do(func() {
+		if err := tx.QueryRow("SELECT @@max_allowed_packet").Scan(&packet.bytes); err != nil {
+			packet.err = errors.Wrap(err, "select max_allowed_packet error")
+		}
+	})
+
+	return packet.bytes, packet.err
+}
+
+// full reports whether a batch of rows and estimated bytes can't take one more row
+func (l batchLimit) full(rows, bytes int) bool {
+	if rows >= l.rows {
+		return true
+	}
+
+	return l.bytes > 0 && bytes > l.bytes
+}
+
+// batchRow is a row buffered for bulk insert
+type batchRow struct {
+	row   map[string]string
+	value []interface{}
+	line  int
+}
+
+// estimateRowSize estimates the bytes of one row in a bulk insert statement
+func estimateRowSize(columns []string, row map[string]string) int {
+	// "(", ")" and ","
+	size := 3
+	for _, column := range columns {
+		// placeholder, separator and length of value
+		size += len(row[column]) + 10
+	}
+
+	return size
+}
+
+// isStatementError reports whether err of mysql fails only the statement, not the transaction.
+// A deadlock and a lock wait timeout (with innodb_rollback_on_timeout) roll back the transaction.
+func isStatementError(err error) bool {
+	mysqlErr, ok := errors.Cause(err).(*mysql.MySQLError)
+	if !ok {
+		return false
+	}
+
+	switch mysqlErr.Number {
+	case mysqlErrLockDeadlock, mysqlErrLockWaitTimeout:
+		return false
+	}
+
+	return true
+}
+
+// execInsert inserts rows of src by INSERT statement.
+// When bulkInsert is enabled, rows are flushed every batch so that
+// only one batch is held in memory at a time.
+func (f FixtureLoader) execInsert(tx txmanager.Tx, columns []string, src rowSource, result *LoadResult) error {
+	quotedColumns := make([]string, len(columns))
+	for i, c := range columns {
+		quotedColumns[i] = quote(f.driver, c)
+	}
+
+	newBuilder := func() squirrel.InsertBuilder {
+		builder := f.statementBuilder().Insert(quote(f.driver, f.table)).Columns(quotedColumns...)
+		if f.update {
+			builder = buildOnDuplicate(quotedColumns, builder)
+		}
+		return builder
+	}
+
+	exec := func(builder squirrel.InsertBuilder, rows int) error {
+		query, args, err := builder.ToSql()
+		if err != nil {
+			return err
+		}
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		result.addAffected(int64(rows), res, f.update)
+		return nil
+	}
+
+	// a statement of non-bulk insert is prepared when the query changes.
+	// The query differs only by which columns are DEFAULT, so consecutive rows usually share it,
+	// and only one statement is open at a time not to hit max_prepared_stmt_count.
+	var stmt *sql.Stmt
+	var prepared string
+	defer func() {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}()
+
+	execPrepared := func(builder squirrel.InsertBuilder) error {
+		query, args, err := builder.ToSql()
+		if err != nil {
+			return err
+		}
+
+		if stmt == nil || query != prepared {
+			if stmt != nil {
+				stmt.Close()
+				stmt = nil
+			}
+			stmt, err = tx.Prepare(query)
+			if err != nil {
+				return err
+			}
+			prepared = query
+		}
+
+		res, err := stmt.Exec(args...)
+		if err != nil {
+			return err
+		}
+		result.addAffected(1, res, f.update)
+		return nil
+	}
+
+	rowError := func(r batchRow, err error) error {
+		return &RowError{Line: r.line, Table: f.table, Row: r.row, Err: err}
+	}
+
+	// flush inserts rows of a batch by one statement.
+	// When it fails, rows are retried one by one to find the row which caused the error.
+	// It is only for mysql, because a failed statement aborts the whole transaction of postgres.
+	// Errors which roll back the transaction of mysql are not retried either.
+	flush := func(batch []batchRow) error {
+		builder := newBuilder()
+		for _, r := range batch {
+			builder = builder.Values(r.value...)
+		}
+
+		batchErr := exec(builder, len(batch))
+		if batchErr == nil {
+			return nil
+		}
+
+		if f.driver == MySQL && isStatementError(batchErr) {
+			for _, r := range batch {
+				if err := exec(newBuilder().Values(r.value...), 1); err != nil {
+					return rowError(r, err)
+				}
+			}
+		}
+
+		return errors.Wrapf(batchErr, "table: %s lines: %d-%d", f.table, batch[0].line, batch[len(batch)-1].line)
+	}
+
+	var limit batchLimit
+	if f.bulkInsert {
+		var err error
+		if limit, err = f.newBatchLimit(tx, len(columns)); err != nil {
+			return err
+		}
+	}
+
+	var batch []batchRow
+	size := 0
+	for {
+		row, err := src.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		value := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			value = append(value, insertValue(row[column]))
+		}
+		r := batchRow{row: row, value: value, line: src.position()}
+
+		if !f.bulkInsert {
+			if err := execPrepared(newBuilder().Values(value...)); err != nil {
+				return rowError(r, err)
+			}
+			continue
+		}
+
+		rowSize := estimateRowSize(columns, row)
+		if len(batch) > 0 && limit.full(len(batch), size+rowSize) {
+			if err := flush(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+			size = 0
+		}
+
+		batch = append(batch, r)
+		size += rowSize
+	}
+
+	if len(batch) > 0 {
+		return flush(batch)
+	}
+
+	return nil
+}
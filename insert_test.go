@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+func TestNewBatchLimit(t *testing.T) {
+	type Test struct {
+		Title   string
+		Limit   int
+		Columns int
+		Output  int
+	}
+
+	tests := []Test{
+		Test{Title: "bulkInsertLimit is upper bound", Limit: 2000, Columns: 2, Output: 2000},
+		Test{Title: "capped by placeholders", Limit: 2000, Columns: 100, Output: 655},
+		Test{Title: "no columns", Limit: 10, Columns: 0, Output: 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			fl := FixtureLoader{driver: PostgreSQL, bulkInsertLimit: test.Limit}
+			limit, err := fl.newBatchLimit(nil, test.Columns)
+			if err != nil {
+				t.Fatalf("[error] new batch limit: %v", err)
+			}
+
+			if limit.rows != test.Output {
+				t.Fatalf("[error] new batch limit: expect: %d but %d", test.Output, limit.rows)
+			}
+		})
+	}
+}
+
+func TestBatchLimitFull(t *testing.T) {
+	limit := batchLimit{rows: 3, bytes: 100}
+
+	if limit.full(2, 100) {
+		t.Fatal("[error] batch limit is not full")
+	}
+
+	if !limit.full(3, 10) {
+		t.Fatal("[error] batch limit is full by rows")
+	}
+
+	if !limit.full(1, 101) {
+		t.Fatal("[error] batch limit is full by bytes")
+	}
+
+	if (batchLimit{rows: 3}).full(1, 1<<30) {
+		t.Fatal("[error] batch limit without bytes is not full by bytes")
+	}
+}
+
+func TestMaxAllowedPacket(t *testing.T) {
+	packet := &packetSize{}
+	packet.once.Do(func() { packet.bytes = 4096 })
+
+	// the cached value is used without querying by tx
+	fl := FixtureLoader{driver: MySQL, bulkInsertLimit: 2000, packet: packet}
+	limit, err := fl.newBatchLimit(nil, 2)
+	if err != nil {
+		t.Fatalf("[error] new batch limit: %v", err)
+	}
+
+	if limit.bytes != 4096-packetHeadroom {
+		t.Fatalf("[error] new batch limit: expect: %d but %d", 4096-packetHeadroom, limit.bytes)
+	}
+}
+
+func TestIsStatementError(t *testing.T) {
+	type Test struct {
+		Title  string
+		Error  error
+		Output bool
+	}
+
+	tests := []Test{
+		Test{Title: "duplicate entry", Error: &mysql.MySQLError{Number: 1062}, Output: true},
+		Test{Title: "deadlock", Error: &mysql.MySQLError{Number: 1213}, Output: false},
+		Test{Title: "lock wait timeout", Error: errors.Wrap(&mysql.MySQLError{Number: 1205}, "exec"), Output: false},
+		Test{Title: "not mysql error", Error: driver.ErrBadConn, Output: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			if ok := isStatementError(test.Error); ok != test.Output {
+				t.Fatalf("[error] is statement error: expect: %v but %v", test.Output, ok)
+			}
+		})
+	}
+}
+
+func TestExecInsertPrepared(t *testing.T) {
+	recorder, db := openRecorder()
+	defer db.Close()
+
+	data := Data{
+		columns: []string{"id", "name"},
+		rows: []map[string]string{
+			map[string]string{"id": "1", "name": "Excalibur"},
+			map[string]string{"id": "2", "name": "Masamune"},
+			map[string]string{"id": "3", "name": ""},
+			map[string]string{"id": "4", "name": "Durandal"},
+			map[string]string{"id": "5", "name": "Muramasa"},
+		},
+	}
+
+	tx, err := txmanager.NewDB(db).TxBegin()
+	if err != nil {
+		t.Fatalf("[error] begin: %v", err)
+	}
+	defer tx.TxFinish()
+
+	fl := FixtureLoader{driver: PostgreSQL, table: "item"}
+	var result LoadResult
+	if err := fl.execInsert(tx, data.columns, newDataSource(data), &result); err != nil {
+		t.Fatalf("[error] exec insert: %v", err)
+	}
+
+	// a statement is prepared again only when DEFAULT columns change
+	expect := []string{
+		`INSERT INTO "item" ("id","name") VALUES ($1,$2)`,
+		`INSERT INTO "item" ("id","name") VALUES ($1,DEFAULT)`,
+		`INSERT INTO "item" ("id","name") VALUES ($1,$2)`,
+	}
+	if !reflect.DeepEqual(recorder.prepares, expect) {
+		t.Fatalf("[error] exec insert: expect: %v but %v", expect, recorder.prepares)
+	}
+
+	if result.RowsInserted != 5 {
+		t.Fatalf("[error] exec insert: expect: 5 rows but %+v", result)
+	}
+}
@@ -1,35 +1,54 @@
 package loader
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+
+	"github.com/pkg/errors"
 )
 
-func (fx FixtureLoader) getDataFromJSON(file string) (Data, error) {
-	f, err := ioutil.ReadFile(file)
+func parseJSON(r io.Reader) (Data, error) {
+	f, err := ioutil.ReadAll(r)
 	if err != nil {
-		return Data{}, err
+		return Data{}, errors.Wrap(err, "read error")
 	}
 
-	var jsonData interface{}
-	json.Unmarshal(f, &jsonData)
-
-	if jsonData == nil {
-		return Data{}, fmt.Errorf("[error] please check file data format")
+	decoder := json.NewDecoder(bytes.NewReader(f))
+	token, err := decoder.Token()
+	if err != nil {
+		return Data{}, errors.Wrapf(ErrMalformedFixture, "%v", err)
+	}
+	if token != json.Delim('[') {
+		return Data{}, errors.Wrap(ErrMalformedFixture, "json must be an array of objects")
 	}
 
 	rows := make([]map[string]string, 0)
-	for _, d := range jsonData.([]interface{}) {
-		if _, ok := d.(map[string]interface{}); !ok {
-			return Data{}, fmt.Errorf("[error] please check json data format not. format isn't map[string]interface{} ")
+	lines := make([]int, 0)
+	for decoder.More() {
+		line := lineAt(f, decoder.InputOffset())
+
+		var d interface{}
+		if err := decoder.Decode(&d); err != nil {
+			return Data{}, errors.Wrapf(ErrMalformedFixture, "line: %d: %v", line, err)
+		}
+		object, ok := d.(map[string]interface{})
+		if !ok {
+			return Data{}, errors.Wrapf(ErrMalformedFixture, "line: %d: row must be an object", line)
 		}
-		row := stringInterfaceToMapString(d.(map[string]interface{}))
+		row := stringInterfaceToMapString(object)
 		rows = append(rows, row)
+		lines = append(lines, line)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return Data{}, errors.Wrapf(ErrMalformedFixture, "%v", err)
 	}
 
 	if len(rows) < 1 {
-		return Data{}, fmt.Errorf("[error] %s is data empty", file)
+		return Data{}, ErrEmptyFixture
 	}
 	columns := make([]string, 0)
 	for key := range rows[0] {
@@ -39,11 +58,22 @@ func (fx FixtureLoader) getDataFromJSON(file string) (Data, error) {
 	data := Data{
 		columns: columns,
 		rows:    rows,
+		lines:   lines,
 	}
 
 	return data, nil
 }
 
+// lineAt returns the line number of the value which starts from offset.
+// offset may point the separator before the value, so white spaces and comma are skipped.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,"), data[offset]) >= 0 {
+		offset++
+	}
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
 func stringInterfaceToMapString(d map[string]interface{}) map[string]string {
 	row := make(map[string]string, len(d))
 	for key, value := range d {
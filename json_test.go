@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestGetDataFromJSON(t *testing.T) {
@@ -15,7 +17,7 @@ func TestGetDataFromJSON(t *testing.T) {
 	t.Run("load json", func(t *testing.T) {
 		file := "_data/item.json"
 
-		data, err := fx.getDataFromJSON(file)
+		data, err := readTestFile(fx, "json", file)
 		if err != nil {
 			t.Fatalf("[error] get data from json: %v", err)
 		}
@@ -29,5 +31,25 @@ func TestGetDataFromJSON(t *testing.T) {
 		if !reflect.DeepEqual(data.rows, rows) {
 			t.Fatalf("[error] get data from csv: expect: %v but %v", data.rows, rows)
 		}
+
+		if !reflect.DeepEqual(data.lines, []int{2, 6}) {
+			t.Fatalf("[error] get data from json: expect: %v but %v", []int{2, 6}, data.lines)
+		}
+	})
+
+	t.Run("load malformed json", func(t *testing.T) {
+		tests := map[string]error{
+			"_data/malformed/map.json":    ErrMalformedFixture,
+			"_data/malformed/broken.json": ErrMalformedFixture,
+			"_data/malformed/scalar.json": ErrMalformedFixture,
+			"_data/malformed/empty.json":  ErrEmptyFixture,
+		}
+
+		for file, expect := range tests {
+			_, err := readTestFile(fx, "json", file)
+			if !errors.Is(err, expect) {
+				t.Fatalf("[error] get data from json %s: expect: %v but %v", file, expect, err)
+			}
+		}
 	})
 }
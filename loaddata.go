@@ -0,0 +1,149 @@
+package loader
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/shogo82148/txmanager"
+)
+
+var loadDataSeq uint64
+
+var loadDataReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x00", `\0`,
+)
+
+// canLoadData reports whether rows can be loaded by LOAD DATA LOCAL INFILE.
+// `update` needs ON DUPLICATE KEY UPDATE, so it falls back to INSERT.
+func (f FixtureLoader) canLoadData() bool {
+	return f.loadData && f.driver == MySQL && !f.update
+}
+
+// execLoadData sends rows of src to mysql by LOAD DATA LOCAL INFILE.
+// Rows are re-encoded to the mysql default format (tab separated, backslash escaped)
+// and streamed through the driver's reader handler, so any fixture format can be loaded.
+// Empty values are loaded as the column DEFAULT, same as INSERT.
+func (f FixtureLoader) execLoadData(tx txmanager.Tx, columns []string, src *countingSource, result *LoadResult) error {
+	name := fmt.Sprintf("go-fixture-loader-%d", atomic.AddUint64(&loadDataSeq, 1))
+
+	pr, pw := io.Pipe()
+	mysql.RegisterReaderHandler(name, func() io.Reader {
+		return pr
+	})
+	defer mysql.DeregisterReaderHandler(name)
+
+	done := make(chan error, 1)
+	go func() {
+		err := writeLoadData(pw, columns, src)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	res, err := tx.Exec(buildLoadData(name, quote(MySQL, f.table), columns, f.ignore))
+	// unblock the writer when mysql stopped reading
+	pr.CloseWithError(io.ErrClosedPipe)
+	if werr := <-done; werr != nil && errors.Cause(werr) != io.ErrClosedPipe {
+		return werr
+	}
+	if err != nil {
+		return err
+	}
+
+	// LOAD DATA LOCAL skips duplicate and invalid rows with warnings as IGNORE
+	if !f.ignore {
+		if affected, err := res.RowsAffected(); err == nil && affected < src.count {
+			return errors.Errorf("table: %s %d of %d rows are not loaded", f.table, src.count-affected, src.count)
+		}
+		if err := loadDataWarning(tx); err != nil {
+			return errors.Wrapf(err, "table: %s", f.table)
+		}
+	}
+
+	result.addAffected(src.count, res, false)
+
+	return nil
+}
+
+// loadDataWarning returns the first warning of the last statement as an error
+func loadDataWarning(tx txmanager.Tx) error {
+	rows, err := tx.Query("SHOW WARNINGS")
+	if err != nil {
+		return errors.Wrap(err, "show warnings error")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return errors.Wrap(err, "show warnings error")
+		}
+		if level != "Note" {
+			return errors.Errorf("load data %s %d: %s", strings.ToLower(level), code, message)
+		}
+	}
+
+	return rows.Err()
+}
+
+func buildLoadData(name, table string, columns []string, ignore bool) string {
+	vars := make([]string, len(columns))
+	sets := make([]string, len(columns))
+	for i, c := range columns {
+		vars[i] = fmt.Sprintf("@c%d", i)
+		sets[i] = fmt.Sprintf("%s = IFNULL(%s, DEFAULT(%s))", quote(MySQL, c), vars[i], quote(MySQL, c))
+	}
+
+	modifier := ""
+	if ignore {
+		modifier = " IGNORE"
+	}
+
+	return fmt.Sprintf(
+		"LOAD DATA LOCAL INFILE 'Reader::%s'%s INTO TABLE %s CHARACTER SET utf8mb4 "+
+			`FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n' `+
+			"(%s) SET %s",
+		name, modifier, table, strings.Join(vars, ", "), strings.Join(sets, ", "),
+	)
+}
+
+// writeLoadData writes rows of src as LOAD DATA default format.
+// Empty value is written as \N (NULL) and replaced to DEFAULT by the SET clause.
+func writeLoadData(w io.Writer, columns []string, src rowSource) error {
+	bw := bufio.NewWriter(w)
+	for {
+		row, err := src.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+
+		for i, column := range columns {
+			if i > 0 {
+				bw.WriteByte('\t')
+			}
+			value := row[column]
+			if len(value) == 0 {
+				bw.WriteString(`\N`)
+				continue
+			}
+			loadDataReplacer.WriteString(bw, value)
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return errors.Wrap(err, "write load data error")
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,52 @@
+package loader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteLoadData(t *testing.T) {
+	columns := []string{"id", "name"}
+	data := Data{
+		columns: columns,
+		rows: []map[string]string{
+			map[string]string{"id": "1", "name": "エクスカリバー"},
+			map[string]string{"id": "2", "name": "tab\tnew\nline\\"},
+			map[string]string{"id": "3", "name": ""},
+		},
+	}
+
+	t.Run("write load data", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeLoadData(&buf, columns, newDataSource(data)); err != nil {
+			t.Fatalf("[error] write load data: %v", err)
+		}
+
+		expect := "1\tエクスカリバー\n2\ttab\\tnew\\nline\\\\\n3\t\\N\n"
+		if buf.String() != expect {
+			t.Fatalf("[error] write load data: expect: %q but %q", expect, buf.String())
+		}
+	})
+}
+
+func TestBuildLoadData(t *testing.T) {
+	t.Run("build load data", func(t *testing.T) {
+		query := buildLoadData("r", "`item`", []string{"id", "name"}, false)
+		expect := "LOAD DATA LOCAL INFILE 'Reader::r' INTO TABLE `item` CHARACTER SET utf8mb4 " +
+			`FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n' ` +
+			"(@c0, @c1) SET `id` = IFNULL(@c0, DEFAULT(`id`)), `name` = IFNULL(@c1, DEFAULT(`name`))"
+		if query != expect {
+			t.Fatalf("[error] build load data: expect: %s but %s", expect, query)
+		}
+	})
+
+	t.Run("build load data with ignore", func(t *testing.T) {
+		query := buildLoadData("r", "`item`", []string{"id"}, true)
+		expect := "LOAD DATA LOCAL INFILE 'Reader::r' IGNORE INTO TABLE `item` CHARACTER SET utf8mb4 " +
+			`FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n' ` +
+			"(@c0) SET `id` = IFNULL(@c0, DEFAULT(`id`))"
+		if query != expect {
+			t.Fatalf("[error] build load data: expect: %s but %s", expect, query)
+		}
+	})
+}
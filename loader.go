@@ -3,10 +3,10 @@ package loader
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
@@ -21,10 +21,25 @@ type FixtureLoader struct {
 	ignore     bool
 	delete     bool
 	bulkInsert bool
+	loadData   bool
+	// Parallel loading of multiple fixtures
+	parallelism int
+	commitEach  bool
 	// Load Option
 	table           string
 	format          string
 	bulkInsertLimit int
+	csvMismatch     ColumnMismatch
+	csvDialect      CSVDialect
+	encoding        string
+	columnMap       map[string]string
+	ignoreColumns   map[string]bool
+	rowTransforms   []RowTransformFunc
+	hooks           []loadHook
+	defaults        map[string]string
+	tableDefaults   map[string]map[string]string
+	// max_allowed_packet shared by the tables of one load
+	packet *packetSize
 }
 
 // Option is set load option
@@ -34,11 +49,26 @@ type Option func(*FixtureLoader) error
 type Data struct {
 	columns []string
 	rows    []map[string]string // {column:value}
+	lines   []int               // line number of each row in the file
+	table   string              // table name of multi table formats
+}
+
+// NewData returns Data of rows. Empty value is loaded as DEFAULT.
+// table is used when `table` option is not set, and Parser can leave it empty
+// to load Data into the table of the file name.
+func NewData(table string, columns []string, rows []map[string]string) Data {
+	return Data{
+		table:   table,
+		columns: columns,
+		rows:    rows,
+	}
 }
 
 const (
 	// MySQL is XXX
 	MySQL = "mysql"
+	// PostgreSQL is XXX
+	PostgreSQL = "postgres"
 )
 
 var (
@@ -99,6 +129,7 @@ func BulkInsert(bulk bool) Option {
 }
 
 // BulkInsertLimit is sets the rows limit of one bulkInsert, which is enabled when bulkInsert is true
+// It is the upper bound, one bulkInsert is also capped by the number of placeholders and max_allowed_packet of mysql.
 func BulkInsertLimit(bulkInsertLimit int) Option {
 	return func(f *FixtureLoader) error {
 		if bulkInsertLimit == 0 {
@@ -109,6 +140,151 @@ func BulkInsertLimit(bulkInsertLimit int) Option {
 	}
 }
 
+// UseLoadData is load data by `LOAD DATA LOCAL INFILE` instead of INSERT.
+// It is enabled only for mysql and falls back to INSERT when `update` is set.
+// The server must allow `local_infile`.
+// LOAD DATA LOCAL skips duplicate and invalid rows with warnings as if IGNORE is given,
+// so unless `ignore` is set, the load fails when a row is skipped or a warning is raised.
+func UseLoadData(use bool) Option {
+	return func(f *FixtureLoader) error {
+		f.loadData = use
+		return nil
+	}
+}
+
+// Parallelism is the number of tables loaded concurrently by LoadFixtures.
+// Tables which depend on each other by foreign keys are loaded in the same connection.
+// HookBatch hooks and sql scripts can't be used with it.
+func Parallelism(n int) Option {
+	return func(f *FixtureLoader) error {
+		if n < 1 {
+			return errors.New("error `parallelism` must be greater than 0")
+		}
+		f.parallelism = n
+		return nil
+	}
+}
+
+// DeferCommit is whether LoadFixtures with Parallelism commits the connections only after all of them
+// loaded their tables successfully, default is true. A load error rolls back all connections,
+// but the commits are sequential and not atomic, so a failure of a commit can't undo the
+// connections already committed.
+// Disable it to commit each connection as soon as its tables are loaded.
+func DeferCommit(deferred bool) Option {
+	return func(f *FixtureLoader) error {
+		f.commitEach = !deferred
+		return nil
+	}
+}
+
+// CSVColumnMismatch set how to handle csv rows whose number of fields differs from the header.
+// Default is MismatchStrict.
+func CSVColumnMismatch(mismatch ColumnMismatch) Option {
+	return func(f *FixtureLoader) error {
+		f.csvMismatch = mismatch
+		return nil
+	}
+}
+
+// CSVOptions set the dialect of csv and tsv files
+func CSVOptions(dialect CSVDialect) Option {
+	return func(f *FixtureLoader) error {
+		if dialect.Comma != 0 && dialect.Comma == dialect.Comment {
+			return errors.New("error `comma` and `comment` must be different")
+		}
+		f.csvDialect = dialect
+		return nil
+	}
+}
+
+// Encoding set the character encoding of csv files like "shift_jis", "cp932" and "euc-jp".
+// Files are transcoded to UTF-8 before parsing.
+// UTF-8 and UTF-16 byte order marks are detected automatically regardless of this option.
+func Encoding(name string) Option {
+	return func(f *FixtureLoader) error {
+		if _, err := lookupEncoding(name); err != nil {
+			return err
+		}
+		f.encoding = name
+		return nil
+	}
+}
+
+// ColumnMap set new names of fixture columns like {"Item Name": "name"}.
+// Multiple ColumnMap options are merged.
+func ColumnMap(columns map[string]string) Option {
+	return func(f *FixtureLoader) error {
+		m := make(map[string]string, len(f.columnMap)+len(columns))
+		for from, to := range f.columnMap {
+			m[from] = to
+		}
+		for from, to := range columns {
+			if to == "" {
+				return errors.Errorf("error new name of column %s is empty", from)
+			}
+			m[from] = to
+		}
+		f.columnMap = m
+		return nil
+	}
+}
+
+// IgnoreColumns set fixture columns which are not loaded, such as notes in spreadsheets.
+// Both the names in fixture and the names renamed by ColumnMap are matched.
+func IgnoreColumns(columns ...string) Option {
+	return func(f *FixtureLoader) error {
+		m := make(map[string]bool, len(f.ignoreColumns)+len(columns))
+		for column := range f.ignoreColumns {
+			m[column] = true
+		}
+		for _, column := range columns {
+			m[column] = true
+		}
+		f.ignoreColumns = m
+		return nil
+	}
+}
+
+// RowTransform set a function which transforms each row before insert.
+// Multiple RowTransform options are applied in order. See RowTransformFunc.
+func RowTransform(transform RowTransformFunc) Option {
+	return func(f *FixtureLoader) error {
+		if transform == nil {
+			return errors.New("error `transform` must not be nil")
+		}
+		transforms := make([]RowTransformFunc, 0, len(f.rowTransforms)+1)
+		f.rowTransforms = append(append(transforms, f.rowTransforms...), transform)
+		return nil
+	}
+}
+
+// Defaults set values of columns which are missing or empty in fixtures of all tables.
+// A column missing in a fixture is added only to the tables which have the column.
+// Values are converted to string as RowTransformFunc, and filled before RowTransform is applied.
+// Multiple Defaults options are merged.
+func Defaults(values map[string]interface{}) Option {
+	return func(f *FixtureLoader) error {
+		f.defaults = mergeDefaults(f.defaults, values)
+		return nil
+	}
+}
+
+// TableDefaults set Defaults of the table, which take precedence over Defaults
+func TableDefaults(table string, values map[string]interface{}) Option {
+	return func(f *FixtureLoader) error {
+		if table == "" {
+			return errors.New("error `table` must not be empty")
+		}
+		tableDefaults := make(map[string]map[string]string, len(f.tableDefaults)+1)
+		for t, d := range f.tableDefaults {
+			tableDefaults[t] = d
+		}
+		tableDefaults[table] = mergeDefaults(tableDefaults[table], values)
+		f.tableDefaults = tableDefaults
+		return nil
+	}
+}
+
 // Table set insert table name
 func Table(table string) Option {
 	return func(f *FixtureLoader) error {
@@ -148,161 +324,311 @@ func New(db *sql.DB, driver string, options ...Option) (FixtureLoader, error) {
 
 // LoadFixture is load fixture
 func (fl FixtureLoader) LoadFixture(value interface{}, options ...Option) error {
+	_, err := fl.LoadFixtureWithResult(value, options...)
+	return err
+}
+
+// LoadFixtureWithResult is load fixture and returns the result of each loaded table
+func (fl FixtureLoader) LoadFixtureWithResult(value interface{}, options ...Option) ([]LoadResult, error) {
 
 	f := fl
 
 	for _, option := range options {
 		if err := option(&f); err != nil {
-			return errors.Wrap(err, "error invalid option")
+			return nil, errors.Wrap(err, "error invalid option")
 		}
 	}
+	f.packet = &packetSize{}
 
 	if v, ok := value.(Data); ok {
-		return f.loadFixtureFromData(v, options...)
+		return f.loadBatch(func(f FixtureLoader) ([]LoadResult, error) {
+			result, err := f.loadFixtureFromData(v)
+			if err != nil {
+				return nil, err
+			}
+			return []LoadResult{result}, nil
+		})
 	}
 
-	var file string
-	if v, ok := value.(string); ok {
-		file = v
-	} else {
-		log.Fatalf("%v is not file string", value)
+	file, ok := value.(string)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupportedValue, "%v(%T) is neither file name nor Data", value, value)
 	}
 
-	if f.table == "" {
-		basename := path.Base(file)
-		match := baseNameRegexp.FindStringSubmatch(basename)
-		if len(match) < 2 {
-			return fmt.Errorf("Please check file name")
+	fixtures, err := f.fileFixtures(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.loadBatch(func(f FixtureLoader) ([]LoadResult, error) {
+		if len(fixtures) == 1 {
+			result, err := f.loadFixture(fixtures[0])
+			if err != nil {
+				return nil, err
+			}
+			return []LoadResult{result}, nil
 		}
-		f.table = match[1]
+
+		deps, err := f.foreignKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		return f.loadSerial(sortFixtures(fixtures, deps))
+	})
+}
+
+// fileFormat returns `format` option or the extension of file
+func (f FixtureLoader) fileFormat(file string) (string, error) {
+	if f.format != "" {
+		return f.format, nil
+	}
+
+	// the format of compressed file is the extension before the compression suffix
+	_, name := compression(file)
+	match := formatRegexp.FindStringSubmatch(name)
+	if len(match) < 2 {
+		return "", errors.Wrapf(ErrUnsupportedFormat, "file: %s has no extension", file)
 	}
 
-	if f.format == "" {
-		match := formatRegexp.FindStringSubmatch(file)
-		if len(match) < 2 {
-			return fmt.Errorf("Please check file format")
+	return match[1], nil
+}
+
+// fileFixtures returns fixtures of file. A file of sourceParser and sql script is one fixture
+// which is read while loading, and others are parsed into fixtures of each Data.
+// Data without table name is loaded into `table` option or the table of the file name.
+func (f FixtureLoader) fileFixtures(file string) ([]fixture, error) {
+	return f.namedFixtures(file, file)
+}
+
+// namedFixtures is fileFixtures of file which is shown as name in errors, such as an entry of archive.
+func (f FixtureLoader) namedFixtures(file, name string) ([]fixture, error) {
+	defaultTable := func() (string, error) {
+		if f.table != "" {
+			return f.table, nil
 		}
-		f.format = match[1]
+		table, err := tableName(file)
+		if err != nil {
+			return "", errors.Wrapf(ErrInvalidFileName, "file: %s", name)
+		}
+		return table, nil
+	}
+
+	format, err := f.fileFormat(file)
+	if err != nil {
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "file: %s has no extension", name)
+	}
+
+	p, err := lookupParser(format)
+	if err != nil {
+		return nil, err
+	}
+
+	_, script := p.(scriptParser)
+	if _, ok := p.(sourceParser); ok || script {
+		table, err := defaultTable()
+		if err != nil {
+			return nil, err
+		}
+		return []fixture{fixture{file: file, name: name, table: table, script: script}}, nil
+	}
+
+	datas, err := parseFile(p, file, name)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]fixture, 0, len(datas))
+	for i := range datas {
+		table := datas[i].table
+		// Table option overrides the table name of a file of one table
+		if table == "" || (f.table != "" && len(datas) == 1) {
+			table, err = defaultTable()
+			if err != nil {
+				return nil, err
+			}
+		}
+		fixtures = append(fixtures, fixture{file: file, name: name, table: table, data: &datas[i]})
+	}
+
+	return fixtures, nil
+}
+
+// loadFixture loads a fixture into its table
+func (f FixtureLoader) loadFixture(fx fixture) (LoadResult, error) {
+	for _, option := range fx.options {
+		if err := option(&f); err != nil {
+			return LoadResult{}, errors.Wrap(err, "error invalid option")
+		}
+	}
+	f.table = fx.table
+	if fx.data == nil {
+		return f.loadFile(fx.file, fx.name)
 	}
 
-	var data Data
-	var err error
+	result, err := f.loadFixtureFromSource(newDataSource(*fx.data))
+	setErrorFile(err, fx.name)
+
+	return result, err
+}
+
+// loadFile loads sql script or the file of sourceParser into f.table. name is the file name in errors.
+func (f FixtureLoader) loadFile(file, name string) (LoadResult, error) {
+	format, err := f.fileFormat(file)
+	if err != nil {
+		return LoadResult{}, err
+	}
 
-	if f.format == "csv" || f.format == "tsv" {
-		data, err = f.getDataFromCSV(file, f.format)
-	} else if f.format == "json" {
-		data, err = f.getDataFromJSON(file)
-	} else if f.format == "yaml" || f.format == "yml" {
-		data, err = f.getDataFromYAML(file)
-	} else {
-		err = fmt.Errorf("not support format: %s", f.format)
+	p, err := lookupParser(format)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	if _, ok := p.(scriptParser); ok {
+		return f.loadScript(file, name)
+	}
+	sp, ok := p.(sourceParser)
+	if !ok {
+		return LoadResult{}, errors.Wrapf(ErrUnsupportedFormat, "format: %s is not read while loading", format)
 	}
 
+	r, err := openFixture(file)
 	if err != nil {
-		return err
+		return LoadResult{}, err
 	}
 
-	return f.LoadFixture(data, options...)
+	src, err := sp.source(f, name, r)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	defer src.close()
+
+	result, err := f.loadFixtureFromSource(src)
+	setErrorFile(err, name)
+
+	return result, err
 }
 
-func (fl FixtureLoader) loadFixtureFromData(data Data, options ...Option) error {
+// tableName returns table name from file name
+func tableName(file string) (string, error) {
+	basename := path.Base(file)
+	match := baseNameRegexp.FindStringSubmatch(basename)
+	if len(match) < 2 {
+		return "", errors.Wrapf(ErrInvalidFileName, "file: %s", file)
+	}
+
+	return match[1], nil
+}
+
+func (fl FixtureLoader) loadFixtureFromData(data Data, options ...Option) (LoadResult, error) {
 	f := fl
 	for _, option := range options {
 		if err := option(&f); err != nil {
-			return errors.Wrap(err, "error invalid option")
+			return LoadResult{}, errors.Wrap(err, "error invalid option")
+		}
+	}
+	if f.table == "" {
+		f.table = data.table
+	}
+
+	return f.loadFixtureFromSource(newDataSource(data))
+}
+
+// loadFixtureFromSource loads rows while reading them from src in one transaction.
+func (f FixtureLoader) loadFixtureFromSource(rows rowSource) (LoadResult, error) {
+	start := time.Now()
+	if len(f.columnMap) > 0 || len(f.ignoreColumns) > 0 {
+		mapped, err := newMappedSource(rows, f.columnMap, f.ignoreColumns)
+		if err != nil {
+			return LoadResult{Table: f.table}, err
+		}
+		rows = mapped
+	}
+	defaults, err := f.columnDefaults(rows.columns())
+	if err != nil {
+		return LoadResult{Table: f.table}, err
+	}
+	if len(defaults) > 0 {
+		rows = newDefaultsSource(rows, defaults)
+	}
+	if len(f.rowTransforms) > 0 {
+		transformed, err := newTransformSource(rows, f.table, f.rowTransforms)
+		if err != nil {
+			return LoadResult{Table: f.table}, err
 		}
+		rows = transformed
 	}
+	src := &countingSource{rowSource: rows}
+	result := LoadResult{Table: f.table}
 
 	tx, err := f.txManager.TxBegin()
 	if err != nil {
-		return err
+		return result, err
 	}
 	defer tx.TxFinish()
 
-	if f.delete {
-		query, args, err := squirrel.Delete(quote(f.table)).ToSql()
-		if err != nil {
+	commit := func() (LoadResult, error) {
+		if err := f.runHooks(tx, HookTable, true); err != nil {
 			tx.TxRollback()
-			return err
+			return result, err
 		}
-		tx.Exec(query, args...)
-	}
-
-	if len(data.rows) == 0 {
 		if err := tx.TxCommit(); err != nil {
-			return err
+			return result, err
 		}
-		return nil
+		result.RowsRead = src.count
+		result.Elapsed = time.Since(start)
+		return result, nil
 	}
 
-	rows := make([][]interface{}, 0, len(data.rows))
-	for _, row := range data.rows {
-		value := make([]interface{}, 0)
-		for _, column := range data.columns {
-			value = append(value, insertValue(row[column]))
-		}
-		rows = append(rows, value)
+	if err := f.runHooks(tx, HookTable, false); err != nil {
+		tx.TxRollback()
+		return result, err
 	}
 
-	var query string
-	var args []interface{}
-
-	quotedColumns := make([]string, len(data.columns))
-	for i, c := range data.columns {
-		quotedColumns[i] = quote(c)
+	if f.delete {
+		query, args, err := f.statementBuilder().Delete(quote(f.driver, f.table)).ToSql()
+		if err != nil {
+			tx.TxRollback()
+			return result, err
+		}
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			tx.TxRollback()
+			return result, errors.Wrapf(err, "table: %s delete error", f.table)
+		}
+		result.RowsDeleted, _ = res.RowsAffected()
+		result.Statements++
 	}
 
-	builder := squirrel.Insert(quote(f.table)).Columns(quotedColumns...)
-	if f.update {
-		builder = buildOnDuplicate(quotedColumns, builder)
+	// a table without columns has no rows to load, e.g. an empty element of xml
+	columns := src.columns()
+	if len(columns) == 0 {
+		return commit()
 	}
 
-	if f.bulkInsert {
-		for i, value := range rows {
-			builder = builder.Values(value...)
-			if (i+1)%f.bulkInsertLimit == 0 {
-				query, args, err = builder.ToSql()
-				if err != nil {
-					break
-				}
-
-				_, err = tx.Exec(query, args...)
-				if err != nil {
-					break
-				}
-				builder = squirrel.Insert(quote(f.table)).Columns(quotedColumns...)
-			}
-		}
-
-		if len(rows)%f.bulkInsertLimit != 0 {
-			query, args, err = builder.ToSql()
-			_, err = tx.Exec(query, args...)
+	if f.canLoadData() {
+		if err := f.execLoadData(tx, columns, src, &result); err != nil {
+			tx.TxRollback()
+			return result, errors.Wrap(err, "db load data error")
 		}
-	} else {
-		for _, value := range rows {
-			query, args, err = builder.Values(value...).ToSql()
-			if err != nil {
-				break
-			}
+		return commit()
+	}
 
-			_, err = tx.Exec(query, args...)
-			if err != nil {
-				break
-			}
+	if f.canCopy() {
+		if err := f.execCopy(tx, columns, src, &result); err != nil {
+			tx.TxRollback()
+			return result, errors.Wrap(err, "db copy error")
 		}
+		return commit()
 	}
 
+	err = f.execInsert(tx, columns, src, &result)
 	if err != nil {
 		err = errors.Wrap(err, "db insert error")
 		tx.TxRollback()
-		return err
+		return result, err
 	}
 
-	if err := tx.TxCommit(); err != nil {
-		return err
-	}
-
-	return nil
+	return commit()
 }
 
 func buildOnDuplicate(columns []string, builder squirrel.InsertBuilder) squirrel.InsertBuilder {
@@ -322,7 +648,18 @@ func insertValue(value string) interface{} {
 	return value
 }
 
-// TODO: Make it changeable for each driver
-func quote(s string) string {
+func (f FixtureLoader) statementBuilder() squirrel.StatementBuilderType {
+	if f.driver == PostgreSQL {
+		return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	}
+
+	return squirrel.StatementBuilder
+}
+
+func quote(driver, s string) string {
+	if driver == PostgreSQL {
+		return fmt.Sprintf(`"%s"`, strings.Replace(s, `"`, `""`, -1))
+	}
+
 	return fmt.Sprintf("`%s`", s)
 }
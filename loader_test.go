@@ -130,6 +130,42 @@ func TestNew(t *testing.T) {
 				Error: nil,
 			},
 		},
+		Test{
+			Title: "success: use load data option",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					UseLoadData(true),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          MySQL,
+					loadData:        true,
+					bulkInsertLimit: defaultBulkInsertLimit,
+				},
+				Error: nil,
+			},
+		},
+		Test{
+			Title: "success: use postgres and bulk insert option",
+			Input: Input{
+				Driver: PostgreSQL,
+				Options: []Option{
+					BulkInsert(true),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          PostgreSQL,
+					bulkInsert:      true,
+					bulkInsertLimit: defaultBulkInsertLimit,
+				},
+				Error: nil,
+			},
+		},
 		Test{
 			Title: "error: update support only mysql",
 			Input: Input{
@@ -172,6 +208,183 @@ func TestNew(t *testing.T) {
 				Error: nil,
 			},
 		},
+		Test{
+			Title: "success: set parallelism and defer commit option",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					Parallelism(4),
+					DeferCommit(false),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          MySQL,
+					parallelism:     4,
+					commitEach:      true,
+					bulkInsertLimit: defaultBulkInsertLimit,
+				},
+				Error: nil,
+			},
+		},
+		Test{
+			Title: "error: set parallelism option of zero",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					Parallelism(0),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error `parallelism` must be greater than 0"),
+			},
+		},
+		Test{
+			Title: "success: set csv column mismatch option",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					CSVColumnMismatch(MismatchPad),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          MySQL,
+					bulkInsertLimit: defaultBulkInsertLimit,
+					csvMismatch:     MismatchPad,
+				},
+				Error: nil,
+			},
+		},
+		Test{
+			Title: "error: set csv options with the same comma and comment",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					CSVOptions(CSVDialect{Comma: '#', Comment: '#'}),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error `comma` and `comment` must be different"),
+			},
+		},
+		Test{
+			Title: "error: set unknown encoding",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					Encoding("unknown"),
+				},
+			},
+			Output: Output{
+				Error: errors.New("htmlindex: invalid encoding name"),
+			},
+		},
+		Test{
+			Title: "success: merge columnMap and ignoreColumns options",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					ColumnMap(map[string]string{"Item ID": "id"}),
+					ColumnMap(map[string]string{"Item Name": "name"}),
+					IgnoreColumns("Note"),
+					IgnoreColumns("memo"),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          MySQL,
+					bulkInsertLimit: defaultBulkInsertLimit,
+					columnMap:       map[string]string{"Item ID": "id", "Item Name": "name"},
+					ignoreColumns:   map[string]bool{"Note": true, "memo": true},
+				},
+				Error: nil,
+			},
+		},
+		Test{
+			Title: "error: set columnMap option with empty name",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					ColumnMap(map[string]string{"Note": ""}),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error new name of column Note is empty"),
+			},
+		},
+		Test{
+			Title: "error: set nil rowTransform option",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					RowTransform(nil),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error `transform` must not be nil"),
+			},
+		},
+		Test{
+			Title: "error: set nil hook",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					BeforeLoad(HookTable, nil),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error `hook` must not be nil"),
+			},
+		},
+		Test{
+			Title: "error: set hook of unknown scope",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					AfterLoadSQL(HookScope(2), "SELECT 1"),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error unknown hook scope 2"),
+			},
+		},
+		Test{
+			Title: "success: merge defaults and tableDefaults options",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					Defaults(map[string]interface{}{"status": "active", "tenant_id": 1}),
+					Defaults(map[string]interface{}{"status": "inactive"}),
+					TableDefaults("item", map[string]interface{}{"rarity": 3}),
+				},
+			},
+			Output: Output{
+				Loader: FixtureLoader{
+					txManager:       txmanager.NewDB(nil),
+					driver:          MySQL,
+					bulkInsertLimit: defaultBulkInsertLimit,
+					defaults:        map[string]string{"status": "inactive", "tenant_id": "1"},
+					tableDefaults:   map[string]map[string]string{"item": map[string]string{"rarity": "3"}},
+				},
+				Error: nil,
+			},
+		},
+		Test{
+			Title: "error: set tableDefaults option without table",
+			Input: Input{
+				Driver: MySQL,
+				Options: []Option{
+					TableDefaults("", map[string]interface{}{"rarity": 3}),
+				},
+			},
+			Output: Output{
+				Error: errors.New("error `table` must not be empty"),
+			},
+		},
 		Test{
 			Title: "error: set bulkInsertLimit option of zero",
 			Input: Input{
@@ -207,6 +420,41 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		Driver string
+		Input  string
+		Output string
+	}{
+		{Driver: MySQL, Input: "item", Output: "`item`"},
+		{Driver: PostgreSQL, Input: "item", Output: `"item"`},
+		{Driver: PostgreSQL, Input: `it"em`, Output: `"it""em"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Driver+" "+test.Input, func(t *testing.T) {
+			if got := quote(test.Driver, test.Input); got != test.Output {
+				t.Fatalf("error quote. got:%s want:%s", got, test.Output)
+			}
+		})
+	}
+}
+
+func TestStatementBuilder(t *testing.T) {
+	t.Run("postgres uses dollar placeholder", func(t *testing.T) {
+		fl := FixtureLoader{driver: PostgreSQL}
+		query, _, err := fl.statementBuilder().Insert(`"item"`).Columns(`"id"`, `"name"`).Values(1, "a").ToSql()
+		if err != nil {
+			t.Fatal("error build query", err.Error())
+		}
+
+		want := `INSERT INTO "item" ("id","name") VALUES ($1,$2)`
+		if query != want {
+			t.Fatalf("error invalid query. got:%s want:%s", query, want)
+		}
+	})
+}
+
 func TestLoadFixrure(t *testing.T) {
 	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
 	if err != nil {
@@ -344,6 +592,59 @@ func TestLoadFixrure(t *testing.T) {
 				item{id: 10, name: "item10"},
 			},
 		},
+		Test{
+			Title: "load csv with bulkInsert/bulkInsertLimit option/rows number is not the multiple of bulkInsertLimit",
+			Input: Input{
+				File: "_data/bulkinsert.csv",
+				Options: []Option{
+					Delete(true),
+					BulkInsert(true),
+					BulkInsertLimit(3),
+					Table("item"),
+				},
+			},
+			Output: []item{
+				item{id: 1, name: "item1"},
+				item{id: 2, name: "item2"},
+				item{id: 3, name: "item3"},
+				item{id: 4, name: "item4"},
+				item{id: 5, name: "item5"},
+				item{id: 6, name: "item6"},
+				item{id: 7, name: "item7"},
+				item{id: 8, name: "item8"},
+				item{id: 9, name: "item9"},
+				item{id: 10, name: "item10"},
+			},
+		},
+		Test{
+			Title: "load csv with load data and delete option",
+			Input: Input{
+				File: "_data/item.csv",
+				Options: []Option{
+					Delete(true),
+					UseLoadData(true),
+				},
+			},
+			Output: []item{
+				item{id: 1, name: "エクスカリバー"},
+				item{id: 2, name: "村正"},
+			},
+		},
+		Test{
+			Title: "load csv with load data and update option falls back to insert",
+			Input: Input{
+				File: "_data/item_update.csv",
+				Options: []Option{
+					Update(true),
+					UseLoadData(true),
+					Table("item"),
+				},
+			},
+			Output: []item{
+				item{id: 1, name: "エクスカリバーNew"},
+				item{id: 2, name: "村正New"},
+			},
+		},
 	}
 
 	fl, err := New(db, MySQL)
@@ -389,6 +690,18 @@ func TestLoadFixrure(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("load data fails on skipped rows without ignore option", func(t *testing.T) {
+		err := fl.LoadFixture("_data/duplicate.csv", Table("item"), Delete(true), UseLoadData(true))
+		if err == nil {
+			t.Fatal("[error] load data with duplicate keys must be error")
+		}
+
+		err = fl.LoadFixture("_data/duplicate.csv", Table("item"), Delete(true), UseLoadData(true), Ignore(true))
+		if err != nil {
+			t.Fatal("[error] load data with ignore option:", err.Error())
+		}
+	})
 }
 
 func TestMain(m *testing.M) {
@@ -408,6 +721,12 @@ func TestMain(m *testing.M) {
 		log.Fatalf("[error] create test db %s", err.Error())
 	}
 
+	// for LOAD DATA LOCAL INFILE
+	_, err = db.Exec("SET GLOBAL local_infile = 1")
+	if err != nil {
+		log.Fatalf("[error] enable local_infile %s", err.Error())
+	}
+
 	testMysqld = mysqld
 	defer mysqld.Stop()
 
@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is the list of fixture files loaded by LoadManifest
+type manifest struct {
+	Fixtures []manifestFixture `yaml:"fixtures"`
+}
+
+// manifestFixture is a fixture file and its options in manifest
+type manifestFixture struct {
+	File          string            `yaml:"file"`
+	Table         string            `yaml:"table"`
+	Format        string            `yaml:"format"`
+	ColumnMap     map[string]string `yaml:"column_map"`
+	IgnoreColumns []string          `yaml:"ignore_columns"`
+}
+
+// LoadManifest loads fixture files listed in YAML manifest like below.
+// File paths are relative to the manifest, and a directory is expanded to the files in it.
+// Tables are loaded in the order of foreign key dependency as LoadFixtures.
+//
+//	fixtures:
+//	  - file: item.csv
+//	    table: item
+//	    column_map:
+//	      Item Name: name
+//	    ignore_columns: [Note]
+func (fl FixtureLoader) LoadManifest(file string, options ...Option) error {
+	_, err := fl.LoadManifestWithResult(file, options...)
+	return err
+}
+
+// LoadManifestWithResult is LoadManifest and returns the result of each loaded table
+func (fl FixtureLoader) LoadManifestWithResult(file string, options ...Option) ([]LoadResult, error) {
+	f := fl
+	for _, option := range options {
+		if err := option(&f); err != nil {
+			return nil, errors.Wrap(err, "error invalid option")
+		}
+	}
+
+	m, err := readManifest(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]fixture, 0, len(m.Fixtures))
+	for i, entry := range m.Fixtures {
+		fxs, err := f.manifestFixtures(filepath.Dir(file), entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "manifest: %s fixture: %d", file, i+1)
+		}
+		fixtures = append(fixtures, fxs...)
+	}
+
+	return f.loadFixtureSet(fixtures)
+}
+
+func readManifest(file string) (manifest, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return manifest{}, errors.Wrapf(err, "manifest: %s open error", file)
+	}
+
+	var m manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil {
+		return manifest{}, errors.Wrapf(ErrMalformedFixture, "manifest: %s: %v", file, err)
+	}
+
+	for i, entry := range m.Fixtures {
+		if entry.File == "" {
+			return manifest{}, errors.Wrapf(ErrMalformedFixture, "manifest: %s fixture: %d has no file", file, i+1)
+		}
+	}
+
+	return m, nil
+}
+
+// manifestFixtures returns fixtures of the entry of manifest in dir
+func (f FixtureLoader) manifestFixtures(dir string, entry manifestFixture) ([]fixture, error) {
+	var options []Option
+	if entry.Format != "" {
+		options = append(options, Format(entry.Format))
+	}
+	if len(entry.ColumnMap) > 0 {
+		options = append(options, ColumnMap(entry.ColumnMap))
+	}
+	if len(entry.IgnoreColumns) > 0 {
+		options = append(options, IgnoreColumns(entry.IgnoreColumns...))
+	}
+
+	g := f
+	for _, option := range append(options, Table(entry.Table)) {
+		if err := option(&g); err != nil {
+			return nil, errors.Wrap(err, "error invalid option")
+		}
+	}
+
+	file := entry.File
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+
+	paths, err := expandPath(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]fixture, 0, len(paths))
+	for _, p := range paths {
+		fxs, err := g.fileFixtures(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, fx := range fxs {
+			fx.options = options
+			fixtures = append(fixtures, fx)
+		}
+	}
+
+	return fixtures, nil
+}
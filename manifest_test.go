@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Run("read manifest", func(t *testing.T) {
+		m, err := readManifest("_data/manifest/manifest.yaml")
+		if err != nil {
+			t.Fatalf("[error] read manifest: %v", err)
+		}
+
+		expect := manifest{
+			Fixtures: []manifestFixture{
+				manifestFixture{
+					File:          "guild.csv",
+					ColumnMap:     map[string]string{"Guild ID": "id", "Guild Name": "name"},
+					IgnoreColumns: []string{"Note"},
+				},
+				manifestFixture{
+					File:          "members.json",
+					Table:         "player",
+					IgnoreColumns: []string{"memo"},
+				},
+				manifestFixture{
+					File: "../fixtures/player_item.csv",
+				},
+			},
+		}
+		if !reflect.DeepEqual(m, expect) {
+			t.Fatalf("[error] read manifest: expect: %v but %v", expect, m)
+		}
+	})
+
+	t.Run("read manifest with unknown field", func(t *testing.T) {
+		_, err := readManifest("_data/malformed/manifest.yaml")
+		if !errors.Is(err, ErrMalformedFixture) {
+			t.Fatalf("[error] read manifest: expect: %v but %v", ErrMalformedFixture, err)
+		}
+	})
+}
+
+func TestManifestFixtures(t *testing.T) {
+	m, err := readManifest("_data/manifest/manifest.yaml")
+	if err != nil {
+		t.Fatalf("[error] read manifest: %v", err)
+	}
+
+	fl := FixtureLoader{table: "ignored"}
+	type Output struct {
+		File    string
+		Table   string
+		Options int
+	}
+	expect := []Output{
+		Output{File: "_data/manifest/guild.csv", Table: "guild", Options: 2},
+		Output{File: "_data/manifest/members.json", Table: "player", Options: 1},
+		Output{File: "_data/fixtures/player_item.csv", Table: "player_item", Options: 0},
+	}
+
+	for i, entry := range m.Fixtures {
+		fixtures, err := fl.manifestFixtures("_data/manifest", entry)
+		if err != nil {
+			t.Fatalf("[error] manifest fixtures: %v", err)
+		}
+
+		if len(fixtures) != 1 {
+			t.Fatalf("[error] manifest fixtures: expect: 1 fixture but %d", len(fixtures))
+		}
+		got := Output{File: fixtures[0].file, Table: fixtures[0].table, Options: len(fixtures[0].options)}
+		if got != expect[i] {
+			t.Fatalf("[error] manifest fixtures: expect: %v but %v", expect[i], got)
+		}
+	}
+	t.Run("script by format of entry", func(t *testing.T) {
+		fixtures, err := FixtureLoader{}.manifestFixtures("_data", manifestFixture{File: "malformed/item.txt", Format: "sql"})
+		if err != nil {
+			t.Fatalf("[error] manifest fixtures: %v", err)
+		}
+
+		if !fixtures[0].script {
+			t.Fatalf("[error] manifest fixtures: expect: script but %v", fixtures[0])
+		}
+	})
+}
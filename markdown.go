@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	headingRegexp        = regexp.MustCompile(`^#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+	htmlCommentRegexp    = regexp.MustCompile(`^<!--\s*(?:table:\s*)?(.*?)\s*-->$`)
+	delimiterRowRegexp   = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+	markdownEscapeRegexp = regexp.MustCompile(`\\([\\|])`)
+)
+
+// parseMarkdown returns Data of each GitHub flavored pipe table in markdown.
+// The table name is the nearest preceding heading or HTML comment such as `<!-- table: item -->`.
+// Tables in fenced code blocks are ignored.
+func parseMarkdown(r io.Reader) ([]Data, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read error")
+	}
+
+	datas := make([]Data, 0)
+	name := ""
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence = line[:3]
+			continue
+		}
+
+		if match := headingRegexp.FindStringSubmatch(line); match != nil {
+			name = strings.Trim(match[1], "`")
+			continue
+		}
+		if match := htmlCommentRegexp.FindStringSubmatch(line); match != nil {
+			name = strings.Trim(match[1], "`")
+			continue
+		}
+
+		if !strings.Contains(line, "|") || i+1 >= len(lines) || !delimiterRowRegexp.MatchString(lines[i+1]) {
+			continue
+		}
+
+		if name == "" {
+			return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: table has no heading", i+1)
+		}
+
+		columns := splitPipeRow(line)
+		if err := validateHeader(columns); err != nil {
+			return nil, errors.Wrapf(err, "line: %d", i+1)
+		}
+
+		data := Data{table: name, columns: columns}
+		for i += 2; i < len(lines) && lines[i] != "" && strings.Contains(lines[i], "|"); i++ {
+			cells := splitPipeRow(lines[i])
+			// as GitHub flavored markdown, excess cells are ignored and missing cells are empty
+			row := make(map[string]string, len(columns))
+			for j, column := range columns {
+				if j < len(cells) {
+					row[column] = cells[j]
+				}
+			}
+			data.rows = append(data.rows, row)
+			data.lines = append(data.lines, i+1)
+		}
+		i--
+
+		datas = append(datas, data)
+		name = ""
+	}
+
+	if len(datas) < 1 {
+		return nil, ErrEmptyFixture
+	}
+
+	return datas, nil
+}
+
+// splitPipeRow splits a row of pipe table into trimmed cells. `\|` is an escaped pipe.
+func splitPipeRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := make([]string, 0)
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, line[start:i])
+			start = i + 1
+		}
+	}
+	cells = append(cells, line[start:])
+
+	for i, cell := range cells {
+		cells[i] = markdownEscapeRegexp.ReplaceAllString(strings.TrimSpace(cell), "$1")
+	}
+
+	return cells
+}
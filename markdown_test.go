@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGetDataFromMarkdown(t *testing.T) {
+	t.Run("load markdown", func(t *testing.T) {
+		datas, err := parseTestFile("md", "_data/scenario.md")
+		if err != nil {
+			t.Fatalf("[error] get data from markdown: %v", err)
+		}
+
+		expect := []Data{
+			Data{
+				table:   "guild",
+				columns: []string{"id", "name"},
+				rows:    []map[string]string{map[string]string{"id": "1", "name": "guild"}},
+				lines:   []int{9},
+			},
+			Data{
+				table:   "player",
+				columns: []string{"id", "name", "note"},
+				rows: []map[string]string{
+					map[string]string{"id": "1", "name": "player", "note": "a | b"},
+					map[string]string{"id": "2", "name": "`player2`"},
+				},
+				lines: []int{14, 15},
+			},
+		}
+		if !reflect.DeepEqual(datas, expect) {
+			t.Fatalf("[error] get data from markdown: expect: %v but %v", expect, datas)
+		}
+	})
+
+	t.Run("load markdown without table name", func(t *testing.T) {
+		_, err := parseTestFile("md", "_data/malformed/noname.md")
+		if !errors.Is(err, ErrMalformedFixture) {
+			t.Fatalf("[error] get data from markdown: expect: %v but %v", ErrMalformedFixture, err)
+		}
+	})
+}
+
+func TestSplitPipeRow(t *testing.T) {
+	type Test struct {
+		Title  string
+		Line   string
+		Expect []string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "with leading and trailing pipes",
+			Line:   "| a | b |",
+			Expect: []string{"a", "b"},
+		},
+		Test{
+			Title:  "without leading and trailing pipes",
+			Line:   "a | b",
+			Expect: []string{"a", "b"},
+		},
+		Test{
+			Title:  "escaped pipe",
+			Line:   `| a \| b | c \|`,
+			Expect: []string{"a | b", "c |"},
+		},
+		Test{
+			Title:  "empty cell",
+			Line:   "| | b |",
+			Expect: []string{"", "b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			cells := splitPipeRow(test.Line)
+			if !reflect.DeepEqual(cells, test.Expect) {
+				t.Fatalf("[error] split pipe row: expect: %q but %q", test.Expect, cells)
+			}
+		})
+	}
+}
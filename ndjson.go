@@ -0,0 +1,137 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ndjsonSource is rowSource which reads newline delimited json line by line.
+// Each line is one row object, and blank lines are skipped.
+// Columns are the keys of the first row, and later rows must not have other keys.
+type ndjsonSource struct {
+	file    string
+	f       io.ReadCloser
+	reader  *bufio.Reader
+	header  []string
+	known   map[string]bool
+	first   map[string]string
+	line    int
+	rowLine int
+}
+
+// newNDJSONSource returns ndjsonSource which reads f. file is the name used in errors.
+func newNDJSONSource(file string, f io.ReadCloser) (*ndjsonSource, error) {
+	s := &ndjsonSource{
+		file:   file,
+		f:      f,
+		reader: bufio.NewReader(f),
+	}
+
+	first, err := s.read()
+	if err != nil {
+		f.Close()
+		if err == io.EOF {
+			return nil, errors.Wrapf(ErrEmptyFixture, "file: %s", file)
+		}
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(first))
+	known := make(map[string]bool, len(first))
+	for key := range first {
+		columns = append(columns, key)
+		known[key] = true
+	}
+	sort.Strings(columns)
+
+	s.header = columns
+	s.known = known
+	s.first = first
+
+	return s, nil
+}
+
+// read returns the row of the next non blank line
+func (s *ndjsonSource) read() (map[string]string, error) {
+	for {
+		b, err := s.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrapf(err, "file: %s read error", s.file)
+		}
+		if len(b) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		s.line++
+
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		// numbers are kept as written, not converted to float64
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		var d interface{}
+		if err := decoder.Decode(&d); err != nil {
+			return nil, errors.Wrapf(ErrMalformedFixture, "file: %s line: %d: %v", s.file, s.line, err)
+		}
+		if decoder.More() {
+			return nil, errors.Wrapf(ErrMalformedFixture, "file: %s line: %d: line must have one object", s.file, s.line)
+		}
+		object, ok := d.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(ErrMalformedFixture, "file: %s line: %d: row must be an object", s.file, s.line)
+		}
+		s.rowLine = s.line
+
+		row := make(map[string]string, len(object))
+		for key, value := range object {
+			if s.known != nil && !s.known[key] {
+				return nil, errors.Wrapf(ErrMalformedFixture, "file: %s line: %d: unknown column %s", s.file, s.line, key)
+			}
+			row[key] = ndjsonValue(value)
+		}
+
+		return row, nil
+	}
+}
+
+// ndjsonValue converts the value to string. null is loaded as DEFAULT.
+func ndjsonValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+func (s *ndjsonSource) columns() []string {
+	return s.header
+}
+
+func (s *ndjsonSource) next() (map[string]string, error) {
+	if s.first != nil {
+		row := s.first
+		s.first = nil
+		return row, nil
+	}
+
+	return s.read()
+}
+
+func (s *ndjsonSource) position() int {
+	return s.rowLine
+}
+
+func (s *ndjsonSource) close() error {
+	return s.f.Close()
+}
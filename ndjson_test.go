@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGetDataFromNDJSON(t *testing.T) {
+	fx := FixtureLoader{}
+	columns := []string{"id", "name"}
+	rows := []map[string]string{map[string]string{"id": "4", "name": "ホーリーランス"}, map[string]string{"id": "3", "name": "ウィザードロッド"}}
+
+	t.Run("load ndjson", func(t *testing.T) {
+		file := "_data/item.ndjson"
+		data, err := readTestFile(fx, "ndjson", file)
+		if err != nil {
+			t.Fatalf("[error] get data from ndjson: %v", err)
+		}
+
+		if !reflect.DeepEqual(data.columns, columns) {
+			t.Fatalf("[error] get data from ndjson: expect: %v but %v", columns, data.columns)
+		}
+
+		if !reflect.DeepEqual(data.rows, rows) {
+			t.Fatalf("[error] get data from ndjson: expect: %v but %v", rows, data.rows)
+		}
+
+		if !reflect.DeepEqual(data.lines, []int{1, 3}) {
+			t.Fatalf("[error] get data from ndjson: expect: %v but %v", []int{1, 3}, data.lines)
+		}
+	})
+
+	t.Run("load ndjson with number and null", func(t *testing.T) {
+		file := "_data/item_number.ndjson"
+		data, err := readTestFile(fx, "ndjson", file)
+		if err != nil {
+			t.Fatalf("[error] get data from ndjson: %v", err)
+		}
+
+		expect := []map[string]string{map[string]string{"id": "12345678", "name": "", "price": "1.50"}}
+		if !reflect.DeepEqual(data.rows, expect) {
+			t.Fatalf("[error] get data from ndjson: expect: %v but %v", expect, data.rows)
+		}
+	})
+
+	t.Run("load malformed ndjson", func(t *testing.T) {
+		tests := map[string]error{
+			"_data/malformed/array.jsonl":    ErrMalformedFixture,
+			"_data/malformed/broken.jsonl":   ErrMalformedFixture,
+			"_data/malformed/empty.ndjson":   ErrEmptyFixture,
+			"_data/malformed/unknown.ndjson": ErrMalformedFixture,
+		}
+
+		for file, expect := range tests {
+			_, err := readTestFile(fx, "ndjson", file)
+			if !errors.Is(err, expect) {
+				t.Fatalf("[error] get data from ndjson %s: expect: %v but %v", file, expect, err)
+			}
+			if expect == ErrMalformedFixture && !strings.Contains(err.Error(), "line: 2") {
+				t.Fatalf("[error] get data from ndjson %s: error must have line number: %v", file, err)
+			}
+		}
+	})
+}
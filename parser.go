@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Parser parses a fixture file into Data.
+// Data without table name is loaded into the table of the file name or `table` option,
+// and Data made by NewData with table name is loaded into the table,
+// which is overridden by `table` option if the file has only one Data.
+type Parser interface {
+	Parse(r io.Reader) ([]Data, error)
+}
+
+// ParserFunc is an adapter to use a function as Parser
+type ParserFunc func(r io.Reader) ([]Data, error)
+
+// Parse calls p(r)
+func (p ParserFunc) Parse(r io.Reader) ([]Data, error) {
+	return p(r)
+}
+
+// sourceParser is a built-in Parser which reads rows one by one while loading
+type sourceParser interface {
+	Parser
+	source(f FixtureLoader, file string, r io.ReadCloser) (rowSource, error)
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{}
+)
+
+func init() {
+	RegisterFormat("csv", csvParser{format: "csv"})
+	RegisterFormat("tsv", csvParser{format: "tsv"})
+	RegisterFormat("json", singleParser(parseJSON))
+	RegisterFormat("ndjson", ndjsonParser{})
+	RegisterFormat("jsonl", ndjsonParser{})
+	RegisterFormat("yaml", singleParser(parseYAML))
+	RegisterFormat("yml", singleParser(parseYAML))
+	RegisterFormat("toml", ParserFunc(parseTOML))
+	RegisterFormat("xml", ParserFunc(parseXML))
+	RegisterFormat("xlsx", ParserFunc(parseXLSX))
+	RegisterFormat("md", ParserFunc(parseMarkdown))
+	RegisterFormat("markdown", ParserFunc(parseMarkdown))
+	RegisterFormat("sql", scriptParser{})
+}
+
+// RegisterFormat registers Parser of the file extension such as "csv".
+// The parser of the same extension, including built-in formats, is replaced.
+// If p is nil, it panics.
+func RegisterFormat(ext string, p Parser) {
+	if fn, ok := p.(ParserFunc); p == nil || (ok && fn == nil) {
+		panic("loader: RegisterFormat parser is nil")
+	}
+
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[strings.TrimPrefix(ext, ".")] = p
+}
+
+// lookupParser returns Parser of the format
+func lookupParser(format string) (Parser, error) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	p, ok := parsers[format]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "not support format: %s", format)
+	}
+
+	return p, nil
+}
+
+// singleParser is Parser of a format which has only one table
+func singleParser(parse func(r io.Reader) (Data, error)) Parser {
+	return ParserFunc(func(r io.Reader) ([]Data, error) {
+		data, err := parse(r)
+		if err != nil {
+			return nil, err
+		}
+		return []Data{data}, nil
+	})
+}
+
+// parseFile parses file by p. name is the file name in errors.
+func parseFile(p Parser, file, name string) ([]Data, error) {
+	r, err := openFixture(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	datas, err := p.Parse(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "file: %s", name)
+	}
+
+	return datas, nil
+}
+
+// csvParser is Parser of csv and tsv
+type csvParser struct {
+	format string
+}
+
+func (p csvParser) Parse(r io.Reader) ([]Data, error) {
+	src, err := FixtureLoader{}.newCSVSource("", ioutil.NopCloser(r), p.format)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Data{data}, nil
+}
+
+func (p csvParser) source(f FixtureLoader, file string, r io.ReadCloser) (rowSource, error) {
+	return f.newCSVSource(file, r, p.format)
+}
+
+// ndjsonParser is Parser of newline delimited json
+type ndjsonParser struct{}
+
+func (p ndjsonParser) Parse(r io.Reader) ([]Data, error) {
+	src, err := newNDJSONSource("", ioutil.NopCloser(r))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Data{data}, nil
+}
+
+func (p ndjsonParser) source(f FixtureLoader, file string, r io.ReadCloser) (rowSource, error) {
+	return newNDJSONSource(file, r)
+}
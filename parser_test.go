@@ -0,0 +1,190 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// parseTestFile parses file by the registered Parser of format
+func parseTestFile(format, file string) ([]Data, error) {
+	p, err := lookupParser(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFile(p, file, file)
+}
+
+// readTestFile reads Data of file in format which has one table.
+// A file of sourceParser is read as loading with the options of f.
+func readTestFile(f FixtureLoader, format, file string) (Data, error) {
+	p, err := lookupParser(format)
+	if err != nil {
+		return Data{}, err
+	}
+
+	sp, ok := p.(sourceParser)
+	if !ok {
+		datas, err := parseFile(p, file, file)
+		if err != nil {
+			return Data{}, err
+		}
+		return datas[0], nil
+	}
+
+	r, err := openFixture(file)
+	if err != nil {
+		return Data{}, err
+	}
+	src, err := sp.source(f, file, r)
+	if err != nil {
+		return Data{}, err
+	}
+	defer src.close()
+
+	return readAll(src)
+}
+
+// parseKeyValue parses lines of `column=value,...` for test
+func parseKeyValue(r io.Reader) ([]Data, error) {
+	var rows []map[string]string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		row := map[string]string{}
+		for _, field := range strings.Split(scanner.Text(), ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Wrapf(ErrMalformedFixture, "invalid field %s", field)
+			}
+			row[kv[0]] = kv[1]
+		}
+		rows = append(rows, row)
+	}
+
+	return []Data{NewData("", []string{"id", "name"}, rows)}, scanner.Err()
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat(".kv", ParserFunc(parseKeyValue))
+
+	t.Run("lookup registered format", func(t *testing.T) {
+		p, err := lookupParser("kv")
+		if err != nil {
+			t.Fatalf("[error] lookup parser: %v", err)
+		}
+
+		datas, err := p.Parse(strings.NewReader("id=1,name=a\nid=2,name=b"))
+		if err != nil {
+			t.Fatalf("[error] parse: %v", err)
+		}
+
+		expect := []Data{NewData("", []string{"id", "name"}, []map[string]string{
+			map[string]string{"id": "1", "name": "a"},
+			map[string]string{"id": "2", "name": "b"},
+		})}
+		if !reflect.DeepEqual(datas, expect) {
+			t.Fatalf("[error] parse: expect: %v but %v", expect, datas)
+		}
+	})
+
+	t.Run("lookup not registered format", func(t *testing.T) {
+		_, err := lookupParser("unknown")
+		if !errors.Is(err, ErrUnsupportedFormat) {
+			t.Fatalf("[error] lookup parser: expect: %v but %v", ErrUnsupportedFormat, err)
+		}
+	})
+
+	t.Run("replace built-in sql format", func(t *testing.T) {
+		isScript := func() bool {
+			fixtures, err := FixtureLoader{}.fileFixtures("_data/setup.sql")
+			return err == nil && fixtures[0].script
+		}
+		if !isScript() {
+			t.Fatal("[error] sql is script by default")
+		}
+
+		RegisterFormat("sql", ParserFunc(parseKeyValue))
+		defer RegisterFormat("sql", scriptParser{})
+
+		if isScript() {
+			t.Fatal("[error] registered sql format is not script")
+		}
+	})
+
+	t.Run("register nil parser", func(t *testing.T) {
+		for _, p := range []Parser{nil, ParserFunc(nil)} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("[error] register format: expect panic of %#v", p)
+					}
+				}()
+				RegisterFormat("nil", p)
+			}()
+		}
+
+		if _, err := lookupParser("nil"); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Fatalf("[error] lookup parser: expect: %v but %v", ErrUnsupportedFormat, err)
+		}
+	})
+}
+
+func TestFileFixtures(t *testing.T) {
+	type Test struct {
+		Title  string
+		Loader FixtureLoader
+		File   string
+		Expect []string
+		Source bool
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "csv is read while loading",
+			File:   "_data/item.csv",
+			Expect: []string{"item"},
+			Source: true,
+		},
+		Test{
+			Title:  "json is parsed into the table of the file name",
+			File:   "_data/item.json",
+			Expect: []string{"item"},
+		},
+		Test{
+			Title:  "table option",
+			Loader: FixtureLoader{table: "weapon"},
+			File:   "_data/item.yaml",
+			Expect: []string{"weapon"},
+		},
+		Test{
+			Title:  "multiple tables",
+			File:   "_data/dataset.xml",
+			Expect: []string{"guild", "player", "player_item"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			fixtures, err := test.Loader.fileFixtures(test.File)
+			if err != nil {
+				t.Fatalf("[error] file fixtures: %v", err)
+			}
+
+			tables := make([]string, 0, len(fixtures))
+			for _, fx := range fixtures {
+				tables = append(tables, fx.table)
+				if (fx.data == nil) != test.Source {
+					t.Fatalf("[error] file fixtures: data of %s must be parsed: %v", fx.table, !test.Source)
+				}
+			}
+			if !reflect.DeepEqual(tables, test.Expect) {
+				t.Fatalf("[error] file fixtures: expect: %v but %v", test.Expect, tables)
+			}
+		})
+	}
+}
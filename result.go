@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoadResult is the summary of loading one table
+type LoadResult struct {
+	Table string
+	// RowsRead is the number of rows read from fixture
+	RowsRead int64
+	// RowsInserted, RowsUpdated and RowsUnchanged are counted from affected rows.
+	// With `update` and bulk insert, mysql doesn't tell updated rows from unchanged rows
+	// in one statement, so they are estimated.
+	RowsInserted  int64
+	RowsUpdated   int64
+	RowsUnchanged int64
+	// RowsDeleted is the number of rows deleted by `delete` option
+	RowsDeleted int64
+	// Statements is the number of statements executed
+	Statements int64
+	Elapsed    time.Duration
+}
+
+// addAffected counts rows of one statement which inserted rows from its affected rows.
+// mysql's affected rows with ON DUPLICATE KEY UPDATE is 1 per inserted row,
+// 2 per updated row and 0 per unchanged row.
+func (r *LoadResult) addAffected(rows int64, result sql.Result, update bool) {
+	r.Statements++
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.RowsInserted += rows
+		return
+	}
+
+	if !update {
+		r.RowsInserted += affected
+		r.RowsUnchanged += rows - affected
+		return
+	}
+
+	if affected >= rows {
+		updated := affected - rows
+		r.RowsUpdated += updated
+		r.RowsInserted += rows - updated
+		return
+	}
+
+	r.RowsInserted += affected
+	r.RowsUnchanged += rows - affected
+}
+
+// countingSource is rowSource which counts read rows
+type countingSource struct {
+	rowSource
+	count int64
+}
+
+func (s *countingSource) next() (map[string]string, error) {
+	row, err := s.rowSource.next()
+	if err == nil {
+		s.count++
+	}
+
+	return row, err
+}
@@ -0,0 +1,113 @@
+package loader
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type affectedResult int64
+
+func (r affectedResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (r affectedResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+func TestAddAffected(t *testing.T) {
+	type Test struct {
+		Title    string
+		Rows     int64
+		Affected int64
+		Update   bool
+		Output   LoadResult
+	}
+
+	tests := []Test{
+		Test{
+			Title:    "insert",
+			Rows:     3,
+			Affected: 3,
+			Output:   LoadResult{RowsInserted: 3, Statements: 1},
+		},
+		Test{
+			Title:    "insert ignore skips duplicated rows",
+			Rows:     3,
+			Affected: 2,
+			Output:   LoadResult{RowsInserted: 2, RowsUnchanged: 1, Statements: 1},
+		},
+		Test{
+			Title:    "update one row",
+			Rows:     1,
+			Affected: 2,
+			Update:   true,
+			Output:   LoadResult{RowsUpdated: 1, Statements: 1},
+		},
+		Test{
+			Title:    "update unchanged row",
+			Rows:     1,
+			Affected: 0,
+			Update:   true,
+			Output:   LoadResult{RowsUnchanged: 1, Statements: 1},
+		},
+		Test{
+			Title:    "bulk update",
+			Rows:     3,
+			Affected: 4,
+			Update:   true,
+			Output:   LoadResult{RowsInserted: 2, RowsUpdated: 1, Statements: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			var result LoadResult
+			result.addAffected(test.Rows, affectedResult(test.Affected), test.Update)
+			if result != test.Output {
+				t.Fatalf("[error] add affected: expect: %+v but %+v", test.Output, result)
+			}
+		})
+	}
+}
+
+func TestLoadFixtureWithResult(t *testing.T) {
+	db, err := sql.Open("mysql", testMysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("[error] db connection", err.Error())
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE result_item (id INTEGER PRIMARY KEY, name VARCHAR(255)) DEFAULT CHARACTER SET utf8mb4")
+	if err != nil {
+		t.Fatal("[error] create table", err.Error())
+	}
+	defer db.Exec("DROP TABLE result_item")
+
+	fl, err := New(db, MySQL)
+	if err != nil {
+		t.Fatal("[error] new ", err.Error())
+	}
+
+	results, err := fl.LoadFixtureWithResult("_data/item.csv", Table("result_item"))
+	if err != nil {
+		t.Fatal("[error] load fixture:", err.Error())
+	}
+
+	expect := LoadResult{Table: "result_item", RowsRead: 2, RowsInserted: 2, Statements: 2}
+	results[0].Elapsed = 0
+	if len(results) != 1 || results[0] != expect {
+		t.Fatalf("[error] load result: expect: %+v but %+v", expect, results)
+	}
+
+	results, err = fl.LoadFixtureWithResult("_data/item_update.csv", Table("result_item"), Update(true), Delete(false))
+	if err != nil {
+		t.Fatal("[error] load fixture:", err.Error())
+	}
+
+	expect = LoadResult{Table: "result_item", RowsRead: 2, RowsUpdated: 2, Statements: 2}
+	results[0].Elapsed = 0
+	if len(results) != 1 || results[0] != expect {
+		t.Fatalf("[error] load result: expect: %+v but %+v", expect, results)
+	}
+}
@@ -0,0 +1,214 @@
+package loader
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	dollarQuoteRegexp = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+)
+
+// sqlStatement is a statement of sql script and its line number
+type sqlStatement struct {
+	query string
+	line  int
+}
+
+// scriptParser is the built-in Parser of sql script, which is executed instead of parsed
+type scriptParser struct{}
+
+func (p scriptParser) Parse(r io.Reader) ([]Data, error) {
+	return nil, errors.Wrap(ErrUnsupportedFormat, "sql script is executed, not parsed into Data")
+}
+
+// loadScript executes the statements of sql script file in a transaction. name is the file name in errors.
+func (f FixtureLoader) loadScript(file, name string) (LoadResult, error) {
+	start := time.Now()
+	result := LoadResult{Table: f.table}
+
+	script, err := readFixture(file)
+	if err != nil {
+		return result, err
+	}
+
+	statements, err := splitSQL(string(script), f.driver == MySQL)
+	if err != nil {
+		return result, errors.Wrapf(err, "file: %s", name)
+	}
+
+	tx, err := f.txManager.TxBegin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.TxFinish()
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt.query); err != nil {
+			tx.TxRollback()
+			return result, errors.Wrapf(err, "file: %s line: %d: exec error", name, stmt.line)
+		}
+		result.Statements++
+	}
+
+	if err := tx.TxCommit(); err != nil {
+		return result, err
+	}
+	result.Elapsed = time.Since(start)
+
+	return result, nil
+}
+
+// splitSQL splits sql script into statements by the delimiter.
+// The delimiter in quotes and comments is ignored, and DELIMITER directive changes the delimiter.
+// With mysql, backslash escapes in quotes and `#` comments are recognized.
+func splitSQL(script string, mysql bool) ([]sqlStatement, error) {
+	statements := make([]sqlStatement, 0)
+	delimiter := ";"
+	line := 1
+	start := 0
+	startLine := 0 // 0 means the current statement has no content yet
+
+	flush := func(end int) {
+		if startLine > 0 {
+			query := strings.TrimSpace(script[start:end])
+			statements = append(statements, sqlStatement{query: query, line: startLine})
+		}
+		startLine = 0
+	}
+
+	for i := 0; i < len(script); {
+		rest := script[i:]
+		c := script[i]
+
+		if startLine == 0 && isDelimiterDirective(rest) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			fields := strings.Fields(rest[:end])
+			if len(fields) != 2 {
+				return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: invalid DELIMITER", line)
+			}
+			delimiter = fields[1]
+			i += end
+			continue
+		}
+
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case isLineComment(rest, mysql):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			i += end
+		case strings.HasPrefix(rest, "/*") && !strings.HasPrefix(rest, "/*!"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: unterminated comment", line)
+			}
+			line += strings.Count(rest[:end+4], "\n")
+			i += end + 4
+		case strings.HasPrefix(rest, delimiter):
+			flush(i)
+			i += len(delimiter)
+		default:
+			if startLine == 0 {
+				startLine = line
+				start = i
+			}
+
+			n, err := quotedLength(rest, mysql, i == 0 || !isIdentifierChar(script[i-1]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "line: %d", line)
+			}
+			line += strings.Count(rest[:n], "\n")
+			i += n
+		}
+	}
+	flush(len(script))
+
+	return statements, nil
+}
+
+// isDelimiterDirective reports whether s starts with mysql client's DELIMITER directive
+func isDelimiterDirective(s string) bool {
+	const directive = "DELIMITER"
+	if len(s) < len(directive) || !strings.EqualFold(s[:len(directive)], directive) {
+		return false
+	}
+
+	return len(s) == len(directive) || strings.IndexByte(" \t\r\n", s[len(directive)]) >= 0
+}
+
+// isLineComment reports whether s starts with a comment to the end of line.
+// mysql requires a white space after `--`.
+func isLineComment(s string, mysql bool) bool {
+	if mysql && strings.HasPrefix(s, "#") {
+		return true
+	}
+	if !strings.HasPrefix(s, "--") {
+		return false
+	}
+
+	return !mysql || len(s) == 2 || strings.IndexByte(" \t\r\n", s[2]) >= 0
+}
+
+// quotedLength returns the length of the quoted string, quoted identifier,
+// dollar quoted string or executable comment at the head of s. Others are 1 byte.
+func quotedLength(s string, mysql, dollar bool) (int, error) {
+	switch s[0] {
+	case '\'', '"', '`':
+		quote := s[0]
+		for i := 1; i < len(s); i++ {
+			if mysql && quote != '`' && s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] != quote {
+				continue
+			}
+			// doubled quote is an escaped quote
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+		return 0, errors.Wrapf(ErrMalformedFixture, "unterminated quote %c", quote)
+	case '$':
+		tag := dollarQuoteRegexp.FindString(s)
+		if !dollar || tag == "" {
+			return 1, nil
+		}
+		end := strings.Index(s[len(tag):], tag)
+		if end < 0 {
+			return 0, errors.Wrapf(ErrMalformedFixture, "unterminated dollar quote %s", tag)
+		}
+		return len(tag) + end + len(tag), nil
+	case '/':
+		if !strings.HasPrefix(s, "/*!") {
+			return 1, nil
+		}
+		end := strings.Index(s[3:], "*/")
+		if end < 0 {
+			return 0, errors.Wrapf(ErrMalformedFixture, "unterminated comment")
+		}
+		return end + 5, nil
+	}
+
+	return 1, nil
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -0,0 +1,92 @@
+package loader
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSplitSQL(t *testing.T) {
+	type Test struct {
+		Title  string
+		Script string
+		MySQL  bool
+		Expect []sqlStatement
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "split statements",
+			Script: "INSERT INTO item VALUES (1);\nINSERT INTO item VALUES (2)",
+			MySQL:  true,
+			Expect: []sqlStatement{
+				sqlStatement{query: "INSERT INTO item VALUES (1)", line: 1},
+				sqlStatement{query: "INSERT INTO item VALUES (2)", line: 2},
+			},
+		},
+		Test{
+			Title:  "postgres dollar quote and comments",
+			Script: "-- comment;\nCREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;\n/* x; */ SELECT '\\';",
+			Expect: []sqlStatement{
+				sqlStatement{query: "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", line: 2},
+				sqlStatement{query: "SELECT '\\'", line: 3},
+			},
+		},
+	}
+
+	script, err := ioutil.ReadFile("_data/setup.sql")
+	if err != nil {
+		t.Fatalf("[error] read file: %v", err)
+	}
+	tests = append(tests, Test{
+		Title:  "split mysql script",
+		Script: string(script),
+		MySQL:  true,
+		Expect: []sqlStatement{
+			sqlStatement{query: `INSERT INTO item (id, name) VALUES (9, 'it''s; a "sword"')`, line: 2},
+			sqlStatement{query: "INSERT INTO item (id, name)\nVALUES (10, 'back\\'slash;')", line: 4},
+			sqlStatement{query: "CREATE PROCEDURE add_item(IN n VARCHAR(255))\nBEGIN\n  INSERT INTO item (name) VALUES (n);\nEND", line: 8},
+			sqlStatement{query: "/*!40101 SET NAMES utf8mb4 */", line: 13},
+		},
+	})
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			statements, err := splitSQL(test.Script, test.MySQL)
+			if err != nil {
+				t.Fatalf("[error] split sql: %v", err)
+			}
+
+			if !reflect.DeepEqual(statements, test.Expect) {
+				t.Fatalf("[error] split sql: expect: %+v but %+v", test.Expect, statements)
+			}
+		})
+	}
+
+	t.Run("split malformed sql", func(t *testing.T) {
+		for _, script := range []string{
+			"SELECT 'unterminated;",
+			"SELECT 1 /* unterminated;",
+			"SELECT $tag$ unterminated; $$",
+			"DELIMITER\nSELECT 1;",
+		} {
+			_, err := splitSQL(script, true)
+			if !errors.Is(err, ErrMalformedFixture) {
+				t.Fatalf("[error] split sql %q: expect: %v but %v", script, ErrMalformedFixture, err)
+			}
+		}
+	})
+}
+
+func TestLoadScriptWithParallelism(t *testing.T) {
+	fixtures, err := FixtureLoader{}.fileFixtures("_data/setup.sql")
+	if err != nil {
+		t.Fatalf("[error] file fixtures: %v", err)
+	}
+
+	if _, err := (FixtureLoader{parallelism: 2}).loadFixtureSet(fixtures); err == nil {
+		t.Fatal("[error] load sql script with parallelism must be error")
+	}
+}
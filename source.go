@@ -0,0 +1,69 @@
+package loader
+
+import (
+	"io"
+)
+
+// rowSource is a stream of fixture rows.
+// next returns io.EOF when there are no more rows.
+// position returns the line (or record) number of the row returned by the last next.
+type rowSource interface {
+	columns() []string
+	next() (map[string]string, error)
+	position() int
+	close() error
+}
+
+// dataSource is rowSource of already loaded Data
+type dataSource struct {
+	data Data
+	pos  int
+}
+
+func newDataSource(data Data) *dataSource {
+	return &dataSource{data: data}
+}
+
+func (s *dataSource) columns() []string {
+	return s.data.columns
+}
+
+func (s *dataSource) next() (map[string]string, error) {
+	if s.pos >= len(s.data.rows) {
+		return nil, io.EOF
+	}
+	row := s.data.rows[s.pos]
+	s.pos++
+
+	return row, nil
+}
+
+func (s *dataSource) position() int {
+	if s.pos > 0 && s.pos <= len(s.data.lines) {
+		return s.data.lines[s.pos-1]
+	}
+
+	return s.pos
+}
+
+func (s *dataSource) close() error {
+	return nil
+}
+
+// readAll reads all rows from source into Data
+func readAll(src rowSource) (Data, error) {
+	data := Data{columns: src.columns()}
+	for {
+		row, err := src.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return data, err
+		}
+		data.rows = append(data.rows, row)
+		data.lines = append(data.lines, src.position())
+	}
+
+	return data, nil
+}
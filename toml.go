@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// parseTOML returns Data of each array of tables in document order.
+// The key of the array is the table name, and the columns are all keys of its rows.
+func parseTOML(r io.Reader) ([]Data, error) {
+	f, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read error")
+	}
+
+	var document map[string]interface{}
+	meta, err := toml.Decode(string(f), &document)
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedFixture, "%v", err)
+	}
+
+	datas := make([]Data, 0, len(document))
+	loaded := map[string]bool{}
+	for _, key := range meta.Keys() {
+		// Keys has the key of an array of tables for each element
+		if len(key) != 1 || loaded[key[0]] {
+			continue
+		}
+		loaded[key[0]] = true
+
+		tables, ok := document[key[0]].([]map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(ErrMalformedFixture, "%s must be an array of tables", key[0])
+		}
+
+		data := Data{table: key[0]}
+		seen := map[string]bool{}
+		for _, table := range tables {
+			row := make(map[string]string, len(table))
+			for column, value := range table {
+				row[column] = tomlValue(value)
+				if !seen[column] {
+					seen[column] = true
+					data.columns = append(data.columns, column)
+				}
+			}
+			data.rows = append(data.rows, row)
+		}
+		sort.Strings(data.columns)
+
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 1 {
+		return nil, ErrEmptyFixture
+	}
+
+	return datas, nil
+}
+
+// tomlValue converts datetime values to ISO 8601 format
+func tomlValue(value interface{}) string {
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+
+	// local date and time are decoded with these zone names
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
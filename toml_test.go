@@ -0,0 +1,104 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGetDataFromTOML(t *testing.T) {
+	t.Run("load toml", func(t *testing.T) {
+		datas, err := parseTestFile("toml", "_data/tables.toml")
+		if err != nil {
+			t.Fatalf("[error] get data from toml: %v", err)
+		}
+
+		expect := []Data{
+			Data{
+				table:   "guild",
+				columns: []string{"id", "name"},
+				rows:    []map[string]string{map[string]string{"id": "1", "name": "guild"}},
+			},
+			Data{
+				table:   "player",
+				columns: []string{"id", "joined_at", "name"},
+				rows: []map[string]string{
+					map[string]string{"id": "1", "name": "player", "joined_at": "2019-01-02T03:04:05"},
+					map[string]string{"id": "2", "name": "player2", "joined_at": "2019-01-02T03:04:05+09:00"},
+				},
+			},
+		}
+		if !reflect.DeepEqual(datas, expect) {
+			t.Fatalf("[error] get data from toml: expect: %v but %v", expect, datas)
+		}
+	})
+
+	t.Run("load malformed toml", func(t *testing.T) {
+		for _, file := range []string{
+			"_data/malformed/scalar.toml",
+			"_data/malformed/broken.toml",
+		} {
+			_, err := parseTestFile("toml", file)
+			if !errors.Is(err, ErrMalformedFixture) {
+				t.Fatalf("[error] get data from toml %s: expect: %v but %v", file, ErrMalformedFixture, err)
+			}
+		}
+	})
+}
+
+func TestFileFixturesTOML(t *testing.T) {
+	type Test struct {
+		Title  string
+		Loader FixtureLoader
+		File   string
+		Expect []string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "table name from the key",
+			File:   "_data/seed.toml",
+			Expect: []string{"item"},
+		},
+		Test{
+			Title:  "table name from table option",
+			Loader: FixtureLoader{table: "weapon"},
+			File:   "_data/seed.toml",
+			Expect: []string{"weapon"},
+		},
+		Test{
+			Title:  "table names from the keys",
+			File:   "_data/tables.toml",
+			Expect: []string{"guild", "player"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			fixtures, err := test.Loader.fileFixtures(test.File)
+			if err != nil {
+				t.Fatalf("[error] file fixtures: %v", err)
+			}
+
+			tables := make([]string, 0, len(fixtures))
+			for _, fx := range fixtures {
+				tables = append(tables, fx.table)
+			}
+			if !reflect.DeepEqual(tables, test.Expect) {
+				t.Fatalf("[error] file fixtures: expect: %v but %v", test.Expect, tables)
+			}
+		})
+	}
+
+	t.Run("date value", func(t *testing.T) {
+		fixtures, err := FixtureLoader{}.fileFixtures("_data/item.toml")
+		if err != nil {
+			t.Fatalf("[error] file fixtures: %v", err)
+		}
+
+		if value := fixtures[0].data.rows[0]["released_at"]; value != "2019-01-02" {
+			t.Fatalf("[error] file fixtures: expect: %s but %s", "2019-01-02", value)
+		}
+	})
+}
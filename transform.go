@@ -0,0 +1,175 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RowTransformFunc transforms a row of table before insert. It can modify values, add or delete columns
+// and drop the row by returning false. Rows are loaded while reading, so the columns are fixed by
+// the first loaded row: columns can be added or deleted only there, and a column missing in other rows
+// is loaded as DEFAULT.
+// Values of fixture are string, and nil value is loaded as DEFAULT. Other values are
+// converted to string; time.Time is ISO 8601 and bool is 1 or 0.
+type RowTransformFunc func(table string, row map[string]interface{}) (map[string]interface{}, bool, error)
+
+// transformSource is rowSource which applies rowTransforms to each row.
+// Columns are the columns of src without the columns deleted from the first row,
+// and the columns added to the first row in name order.
+// The first row is read ahead to fix the columns.
+type transformSource struct {
+	rowSource
+	table      string
+	transforms []RowTransformFunc
+	header     []string
+	known      map[string]bool
+	first      map[string]string
+	firstLine  int
+	line       int
+}
+
+func newTransformSource(src rowSource, table string, transforms []RowTransformFunc) (*transformSource, error) {
+	columns := src.columns()
+	s := &transformSource{
+		rowSource:  src,
+		table:      table,
+		transforms: transforms,
+		header:     append([]string(nil), columns...),
+		known:      make(map[string]bool, len(columns)),
+	}
+	for _, column := range columns {
+		s.known[column] = true
+	}
+
+	row, values, line, err := s.read()
+	if err != nil {
+		if err == io.EOF {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	// a column of the first row deleted by the transforms isn't loaded
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		_, read := row[column]
+		if _, ok := values[column]; read && !ok {
+			delete(s.known, column)
+			continue
+		}
+		header = append(header, column)
+	}
+	s.header = header
+
+	added := make([]string, 0)
+	for column := range values {
+		if !s.known[column] {
+			s.known[column] = true
+			added = append(added, column)
+		}
+	}
+	sort.Strings(added)
+	s.header = append(s.header, added...)
+
+	s.first, s.firstLine = toStringRow(values), line
+
+	return s, nil
+}
+
+// read returns the next row kept by the transforms, the row before transformed and its line
+func (s *transformSource) read() (map[string]string, map[string]interface{}, int, error) {
+	for {
+		row, err := s.rowSource.next()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		line := s.rowSource.position()
+
+		values := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			values[column] = value
+		}
+
+		keep := true
+		for _, transform := range s.transforms {
+			values, keep, err = transform(s.table, values)
+			if err != nil {
+				return nil, nil, line, &RowError{Line: line, Table: s.table, Row: row, Err: err}
+			}
+			if !keep {
+				break
+			}
+		}
+		if keep {
+			return row, values, line, nil
+		}
+	}
+}
+
+func (s *transformSource) columns() []string {
+	return s.header
+}
+
+func (s *transformSource) next() (map[string]string, error) {
+	if s.first != nil {
+		row := s.first
+		s.first = nil
+		s.line = s.firstLine
+		return row, nil
+	}
+
+	_, values, line, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.line = line
+
+	row := toStringRow(values)
+	for column := range row {
+		if !s.known[column] {
+			err := errors.Wrapf(ErrMalformedFixture, "column %s is not in the first row", column)
+			return nil, &RowError{Line: line, Table: s.table, Row: row, Err: err}
+		}
+	}
+
+	return row, nil
+}
+
+func (s *transformSource) position() int {
+	return s.line
+}
+
+// toStringRow converts values returned by RowTransformFunc to string
+func toStringRow(values map[string]interface{}) map[string]string {
+	row := make(map[string]string, len(values))
+	for column, value := range values {
+		row[column] = transformValue(value)
+	}
+
+	return row
+}
+
+// transformValue converts a value returned by RowTransformFunc to string
+func transformValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return v.Format("2006-01-02T15:04:05.999999999")
+	}
+
+	return fmt.Sprint(value)
+}
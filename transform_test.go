@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestTransformSource(t *testing.T) {
+	data := Data{
+		columns: []string{"id", "name", "password"},
+		rows: []map[string]string{
+			map[string]string{"id": "1", "name": "Arthur", "password": "excalibur"},
+			map[string]string{"id": "2", "name": "Masamune", "password": "muramasa"},
+			map[string]string{"id": "3", "name": "Lancelot", "password": "arondight"},
+		},
+		lines: []int{2, 3, 4},
+	}
+
+	hash := func(table string, row map[string]interface{}) (map[string]interface{}, bool, error) {
+		row["password"] = fmt.Sprintf("hash(%s)", row["password"])
+		row["updated_at"] = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+		row["active"] = true
+		return row, true, nil
+	}
+	drop := func(table string, row map[string]interface{}) (map[string]interface{}, bool, error) {
+		return row, row["id"] != "2", nil
+	}
+
+	t.Run("transform rows", func(t *testing.T) {
+		src, err := newTransformSource(newDataSource(data), "player", []RowTransformFunc{drop, hash})
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+		transformed, err := readAll(src)
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+
+		expect := Data{
+			columns: []string{"id", "name", "password", "active", "updated_at"},
+			rows: []map[string]string{
+				map[string]string{"id": "1", "name": "Arthur", "password": "hash(excalibur)", "active": "1", "updated_at": "2019-01-02T03:04:05"},
+				map[string]string{"id": "3", "name": "Lancelot", "password": "hash(arondight)", "active": "1", "updated_at": "2019-01-02T03:04:05"},
+			},
+			lines: []int{2, 4},
+		}
+		if !reflect.DeepEqual(transformed, expect) {
+			t.Fatalf("[error] transform rows: expect: %v but %v", expect, transformed)
+		}
+	})
+
+	t.Run("transform error", func(t *testing.T) {
+		errTransform := errors.New("transform error")
+		fail := func(table string, row map[string]interface{}) (map[string]interface{}, bool, error) {
+			if row["id"] == "2" {
+				return nil, false, errTransform
+			}
+			return row, true, nil
+		}
+
+		src, err := newTransformSource(newDataSource(data), "player", []RowTransformFunc{fail})
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+		_, err = readAll(src)
+
+		var re *RowError
+		if !errors.As(err, &re) || !errors.Is(err, errTransform) {
+			t.Fatalf("[error] transform rows: expect: RowError but %v", err)
+		}
+		if re.Line != 3 || re.Table != "player" {
+			t.Fatalf("[error] transform rows: expect: line 3 of player but %v", re)
+		}
+	})
+
+	t.Run("replace column", func(t *testing.T) {
+		digest := func(table string, row map[string]interface{}) (map[string]interface{}, bool, error) {
+			row["password_digest"] = fmt.Sprintf("digest(%s)", row["password"])
+			delete(row, "password")
+			return row, true, nil
+		}
+
+		src, err := newTransformSource(newDataSource(data), "player", []RowTransformFunc{digest})
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+		transformed, err := readAll(src)
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+
+		columns := []string{"id", "name", "password_digest"}
+		if !reflect.DeepEqual(transformed.columns, columns) {
+			t.Fatalf("[error] transform rows: expect: %v but %v", columns, transformed.columns)
+		}
+		if transformed.rows[2]["password_digest"] != "digest(arondight)" {
+			t.Fatalf("[error] transform rows: expect: digest(arondight) but %v", transformed.rows[2])
+		}
+	})
+
+	t.Run("add column after the first row", func(t *testing.T) {
+		add := func(table string, row map[string]interface{}) (map[string]interface{}, bool, error) {
+			if row["id"] == "3" {
+				row["note"] = "late"
+			}
+			return row, true, nil
+		}
+
+		src, err := newTransformSource(newDataSource(data), "player", []RowTransformFunc{add})
+		if err != nil {
+			t.Fatalf("[error] transform rows: %v", err)
+		}
+		_, err = readAll(src)
+
+		var re *RowError
+		if !errors.As(err, &re) || !errors.Is(err, ErrMalformedFixture) {
+			t.Fatalf("[error] transform rows: expect: %v but %v", ErrMalformedFixture, err)
+		}
+		if re.Line != 4 {
+			t.Fatalf("[error] transform rows: expect: line 4 but %v", re)
+		}
+	})
+}
+
+func TestTransformValue(t *testing.T) {
+	type Test struct {
+		Title  string
+		Value  interface{}
+		Expect string
+	}
+
+	tests := []Test{
+		Test{Title: "nil is DEFAULT", Value: nil, Expect: ""},
+		Test{Title: "string", Value: "name", Expect: "name"},
+		Test{Title: "bytes", Value: []byte("hash"), Expect: "hash"},
+		Test{Title: "bool", Value: false, Expect: "0"},
+		Test{Title: "int", Value: 42, Expect: "42"},
+		Test{Title: "time", Value: time.Date(2019, 1, 2, 0, 0, 0, 500, time.UTC), Expect: "2019-01-02T00:00:00.0000005"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			if value := transformValue(test.Value); value != test.Expect {
+				t.Fatalf("[error] transform value: expect: %s but %s", test.Expect, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package loader
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tealeg/xlsx"
+)
+
+// parseXLSX returns Data of each sheet. Sheet name is the table name,
+// and the first row is the header. Date cells are converted to ISO 8601 format.
+func parseXLSX(r io.Reader) ([]Data, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read error")
+	}
+
+	book, err := xlsx.OpenBinary(b)
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedFixture, "%v", err)
+	}
+
+	datas := make([]Data, 0, len(book.Sheets))
+	for _, sheet := range book.Sheets {
+		if len(sheet.Rows) == 0 {
+			continue
+		}
+
+		columns := make([]string, 0, len(sheet.Rows[0].Cells))
+		for _, cell := range sheet.Rows[0].Cells {
+			columns = append(columns, strings.TrimSpace(cell.Value))
+		}
+		// trailing blank cells are not columns
+		for len(columns) > 0 && columns[len(columns)-1] == "" {
+			columns = columns[:len(columns)-1]
+		}
+		if err := validateHeader(columns); err != nil {
+			return nil, errors.Wrapf(err, "sheet: %s", sheet.Name)
+		}
+
+		data := Data{table: sheet.Name, columns: columns}
+		for i, r := range sheet.Rows[1:] {
+			if r == nil {
+				continue
+			}
+
+			row := make(map[string]string, len(columns))
+			blank := true
+			for j, cell := range r.Cells {
+				if j >= len(columns) {
+					break
+				}
+				value, err := xlsxCellValue(cell, book.Date1904)
+				if err != nil {
+					return nil, errors.Wrapf(ErrMalformedFixture, "sheet: %s line: %d: %v", sheet.Name, i+2, err)
+				}
+				if value != "" {
+					blank = false
+				}
+				row[columns[j]] = value
+			}
+			if blank {
+				continue
+			}
+
+			data.rows = append(data.rows, row)
+			data.lines = append(data.lines, i+2)
+		}
+
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func xlsxCellValue(cell *xlsx.Cell, date1904 bool) (string, error) {
+	if cell.Value == "" || cell.Type() != xlsx.CellTypeNumeric || !cell.IsTime() {
+		return cell.Value, nil
+	}
+
+	t, err := cell.GetTime(date1904)
+	if err != nil {
+		return "", err
+	}
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02"), nil
+	}
+
+	return t.Format("2006-01-02T15:04:05"), nil
+}
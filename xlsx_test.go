@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGetDataFromXLSX(t *testing.T) {
+	t.Run("load xlsx", func(t *testing.T) {
+		datas, err := parseTestFile("xlsx", "_data/sheets.xlsx")
+		if err != nil {
+			t.Fatalf("[error] get data from xlsx: %v", err)
+		}
+
+		expect := []Data{
+			Data{
+				table:   "item",
+				columns: []string{"id", "name", "created_at"},
+				rows: []map[string]string{
+					map[string]string{"id": "1", "name": "エクスカリバー", "created_at": "2019-01-02"},
+					map[string]string{"id": "2", "name": "", "created_at": "2019-01-02T03:04:05"},
+				},
+				lines: []int{2, 4},
+			},
+			Data{
+				table:   "guild",
+				columns: []string{"id", "name"},
+				rows:    []map[string]string{map[string]string{"id": "1", "name": "guild"}},
+				lines:   []int{2},
+			},
+		}
+		if !reflect.DeepEqual(datas, expect) {
+			t.Fatalf("[error] get data from xlsx: expect: %v but %v", expect, datas)
+		}
+	})
+
+	t.Run("load malformed xlsx", func(t *testing.T) {
+		_, err := parseTestFile("xlsx", "_data/item.csv")
+		if !errors.Is(err, ErrMalformedFixture) {
+			t.Fatalf("[error] get data from xlsx: expect: %v but %v", ErrMalformedFixture, err)
+		}
+	})
+}
+
+func TestExpandFixturesXLSX(t *testing.T) {
+	fixtures, err := FixtureLoader{}.expandFixtures([]string{"_data/sheets.xlsx"})
+	if err != nil {
+		t.Fatalf("[error] expand fixtures: %v", err)
+	}
+
+	tables := make([]string, 0, len(fixtures))
+	for _, fx := range fixtures {
+		if fx.file != "_data/sheets.xlsx" || fx.data == nil {
+			t.Fatalf("[error] expand fixtures: unexpected fixture %v", fx)
+		}
+		tables = append(tables, fx.table)
+	}
+	if !reflect.DeepEqual(tables, []string{"item", "guild"}) {
+		t.Fatalf("[error] expand fixtures: expect: %v but %v", []string{"item", "guild"}, tables)
+	}
+}
@@ -0,0 +1,77 @@
+package loader
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// parseXML returns Data of each table in DBUnit flat XML dataset.
+// Each element in <dataset> is a row of the table of the element name, and its attributes are columns.
+// The columns are all attributes of the table, and missing attributes are loaded as DEFAULT.
+func parseXML(r io.Reader) ([]Data, error) {
+	decoder := xml.NewDecoder(r)
+	datas := make([]Data, 0)
+	index := map[string]int{}
+	seen := map[string]bool{}
+	depth := 0
+
+	for {
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: %v", line, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				if t.Name.Local != "dataset" {
+					return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: root element must be dataset", line)
+				}
+				continue
+			}
+			if depth > 2 {
+				return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: row must not have child elements", line)
+			}
+
+			table := t.Name.Local
+			i, ok := index[table]
+			if !ok {
+				i = len(datas)
+				index[table] = i
+				datas = append(datas, Data{table: table})
+			}
+
+			// an element without attributes is an empty table
+			if len(t.Attr) == 0 {
+				continue
+			}
+
+			row := make(map[string]string, len(t.Attr))
+			for _, attr := range t.Attr {
+				column := attr.Name.Local
+				row[column] = attr.Value
+				if !seen[table+"."+column] {
+					seen[table+"."+column] = true
+					datas[i].columns = append(datas[i].columns, column)
+				}
+			}
+			datas[i].rows = append(datas[i].rows, row)
+			datas[i].lines = append(datas[i].lines, line)
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if len(datas) < 1 {
+		return nil, ErrEmptyFixture
+	}
+
+	return datas, nil
+}
@@ -0,0 +1,54 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGetDataFromXML(t *testing.T) {
+	t.Run("load xml", func(t *testing.T) {
+		datas, err := parseTestFile("xml", "_data/dataset.xml")
+		if err != nil {
+			t.Fatalf("[error] get data from xml: %v", err)
+		}
+
+		expect := []Data{
+			Data{
+				table:   "guild",
+				columns: []string{"id", "name"},
+				rows:    []map[string]string{map[string]string{"id": "1", "name": "guild"}},
+				lines:   []int{3},
+			},
+			Data{
+				table:   "player",
+				columns: []string{"id", "name", "guild_id"},
+				rows: []map[string]string{
+					map[string]string{"id": "1", "name": "player"},
+					map[string]string{"id": "2", "guild_id": "1"},
+				},
+				lines: []int{4, 5},
+			},
+			Data{
+				table: "player_item",
+			},
+		}
+		if !reflect.DeepEqual(datas, expect) {
+			t.Fatalf("[error] get data from xml: expect: %v but %v", expect, datas)
+		}
+	})
+
+	t.Run("load malformed xml", func(t *testing.T) {
+		for _, file := range []string{
+			"_data/malformed/root.xml",
+			"_data/malformed/nested.xml",
+			"_data/malformed/broken.xml",
+		} {
+			_, err := parseTestFile("xml", file)
+			if !errors.Is(err, ErrMalformedFixture) {
+				t.Fatalf("[error] get data from xml %s: expect: %v but %v", file, ErrMalformedFixture, err)
+			}
+		}
+	})
+}
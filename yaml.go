@@ -1,29 +1,48 @@
 package loader
 
 import (
-	"fmt"
+	"io"
 	"io/ioutil"
 
-	"gopkg.in/yaml.v2"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
-func (fx FixtureLoader) getDataFromYAML(file string) (Data, error) {
-	f, err := ioutil.ReadFile(file)
+func parseYAML(r io.Reader) (Data, error) {
+	f, err := ioutil.ReadAll(r)
 	if err != nil {
-		return Data{}, err
+		return Data{}, errors.Wrap(err, "read error")
 	}
 
-	var yamlData interface{}
-	yaml.Unmarshal(f, &yamlData)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(f, &doc); err != nil {
+		return Data{}, errors.Wrapf(ErrMalformedFixture, "%v", err)
+	}
+
+	var items []*yaml.Node
+	if len(doc.Content) > 0 {
+		if doc.Content[0].Kind != yaml.SequenceNode {
+			return Data{}, errors.Wrapf(ErrMalformedFixture, "line: %d: yaml must be a list of mappings", doc.Content[0].Line)
+		}
+		items = doc.Content[0].Content
+	}
 
 	rows := make([]map[string]string, 0)
-	for _, d := range yamlData.([]interface{}) {
-		row := interfaceInterfaceToMapString(d.(map[interface{}]interface{}))
+	lines := make([]int, 0)
+	for _, item := range items {
+		if item.Kind != yaml.MappingNode {
+			return Data{}, errors.Wrapf(ErrMalformedFixture, "line: %d: row must be a mapping", item.Line)
+		}
+		row, err := yamlRow(item)
+		if err != nil {
+			return Data{}, err
+		}
 		rows = append(rows, row)
+		lines = append(lines, item.Line)
 	}
 
 	if len(rows) < 1 {
-		return Data{}, fmt.Errorf("[error] %s is data empty", file)
+		return Data{}, ErrEmptyFixture
 	}
 
 	columns := make([]string, 0)
@@ -34,17 +53,64 @@ func (fx FixtureLoader) getDataFromYAML(file string) (Data, error) {
 	data := Data{
 		columns: columns,
 		rows:    rows,
+		lines:   lines,
 	}
 
 	return data, nil
 }
 
-// onply support testfixtures yaml type refs: https://github.com/go-testfixtures/testfixtures#usage
-func interfaceInterfaceToMapString(d map[interface{}]interface{}) map[string]string {
-	row := make(map[string]string, len(d))
-	for key, value := range d {
-		row[fmt.Sprint(key)] = fmt.Sprint(value)
+// yamlRow returns the row of the mapping node.
+// Values are the scalars as written, so datetimes are not converted, and null is loaded as DEFAULT.
+func yamlRow(item *yaml.Node) (map[string]string, error) {
+	row := make(map[string]string, len(item.Content)/2)
+
+	// merge keys `<<: *base` are expanded first, and the keys of the row take precedence
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		if key.Tag != "!!merge" {
+			continue
+		}
+
+		bases := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			bases = value.Content
+		}
+		// the earlier base takes precedence in a sequence of merge keys
+		for j := len(bases) - 1; j >= 0; j-- {
+			base := bases[j]
+			if base.Kind == yaml.AliasNode {
+				base = base.Alias
+			}
+			if base.Kind != yaml.MappingNode {
+				return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: merge key must refer to mappings", key.Line)
+			}
+			merged, err := yamlRow(base)
+			if err != nil {
+				return nil, err
+			}
+			for column, v := range merged {
+				row[column] = v
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		if key.Tag == "!!merge" {
+			continue
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if key.Kind != yaml.ScalarNode || value.Kind != yaml.ScalarNode {
+			return nil, errors.Wrapf(ErrMalformedFixture, "line: %d: column and value must be scalars", key.Line)
+		}
+
+		row[key.Value] = value.Value
+		if value.Tag == "!!null" {
+			row[key.Value] = ""
+		}
 	}
 
-	return row
+	return row, nil
 }
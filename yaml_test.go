@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestGetDataFromYAML(t *testing.T) {
@@ -13,7 +15,7 @@ func TestGetDataFromYAML(t *testing.T) {
 
 	t.Run("load yaml", func(t *testing.T) {
 		file := "_data/item.yaml"
-		data, err := fx.getDataFromYAML(file)
+		data, err := readTestFile(fx, "yaml", file)
 		if err != nil {
 			t.Fatalf("[error] get data from yaml: %v", err)
 		}
@@ -27,5 +29,55 @@ func TestGetDataFromYAML(t *testing.T) {
 		if !reflect.DeepEqual(data.rows, rows) {
 			t.Fatalf("[error] get data from yaml: expect: %v but %v", data.rows, rows)
 		}
+
+		if !reflect.DeepEqual(data.lines, []int{2, 5}) {
+			t.Fatalf("[error] get data from yaml: expect: %v but %v", []int{2, 5}, data.lines)
+		}
+	})
+
+	t.Run("load yaml with datetime", func(t *testing.T) {
+		file := "_data/item_datetime.yaml"
+		data, err := readTestFile(fx, "yaml", file)
+		if err != nil {
+			t.Fatalf("[error] get data from yaml: %v", err)
+		}
+
+		expect := []map[string]string{
+			map[string]string{"id": "1", "name": "ロングソード", "created_at": "2019-01-02 03:04:05", "released_on": "2019-01-02"},
+			map[string]string{"id": "2", "name": "ショートソード", "created_at": "2019-01-03T04:05:06Z", "released_on": ""},
+		}
+		if !reflect.DeepEqual(data.rows, expect) {
+			t.Fatalf("[error] get data from yaml: expect: %v but %v", expect, data.rows)
+		}
+	})
+
+	t.Run("load yaml with merge keys", func(t *testing.T) {
+		data, err := readTestFile(fx, "yaml", "_data/item_merge.yaml")
+		if err != nil {
+			t.Fatalf("[error] get data from yaml: %v", err)
+		}
+
+		expect := []map[string]string{
+			map[string]string{"id": "1", "name": "ロングソード", "price": "100"},
+			map[string]string{"id": "2", "name": "ショートソード", "price": "100"},
+			map[string]string{"id": "3", "name": "ロングソード", "price": "100", "rarity": "2"},
+		}
+		if !reflect.DeepEqual(data.rows, expect) {
+			t.Fatalf("[error] get data from yaml: expect: %v but %v", expect, data.rows)
+		}
+	})
+
+	t.Run("load malformed yaml", func(t *testing.T) {
+		for _, file := range []string{
+			"_data/malformed/map.yaml",
+			"_data/malformed/broken.yaml",
+			"_data/malformed/scalar.yaml",
+			"_data/malformed/nested.yaml",
+		} {
+			_, err := readTestFile(fx, "yaml", file)
+			if !errors.Is(err, ErrMalformedFixture) {
+				t.Fatalf("[error] get data from yaml %s: expect: %v but %v", file, ErrMalformedFixture, err)
+			}
+		}
 	})
 }